@@ -117,6 +117,27 @@ func (c *Client) DetectNginxIngress() (bool, error) {
 	return false, nil
 }
 
+func (c *Client) DetectIstio() (bool, error) {
+	istiod, err :=
+		c.cs.AppsV1().
+			Deployments("istio-system").
+			Get(context.Background(), "istiod", metav1.GetOptions{})
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("error fetching istiod deployment: %w", err)
+	}
+
+	if app, ok := istiod.ObjectMeta.Labels["app"]; !ok || app != "istiod" {
+		return false, nil
+	}
+
+	return true, nil
+}
+
 func (c *Client) DetectTraefikV2() (bool, error) {
 	// We query for resources to check if available API group traefik.containo.us/v1alpha1 is installed
 	_, err := c.cs.Discovery().ServerResourcesForGroupVersion("traefik.containo.us/v1alpha1")
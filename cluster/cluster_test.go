@@ -243,6 +243,65 @@ func TestClient_DetectLinkerd(t *testing.T) {
 	}
 }
 
+func TestClient_DetectIstio(t *testing.T) {
+	data := []struct {
+		name           string
+		clientset      kubernetes.Interface
+		expectedResult bool
+	}{
+		{
+			name:           "No Istio",
+			clientset:      fake.NewSimpleClientset(),
+			expectedResult: false,
+		},
+		{
+			name: "Invalid istiod Spec",
+			clientset: fake.NewSimpleClientset(
+				&apps_v1.Deployment{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Deployment",
+						APIVersion: "apps/v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "istiod",
+						Namespace: "istio-system",
+					},
+				},
+			),
+			expectedResult: false,
+		},
+		{
+			name: "Valid istiod Spec",
+			clientset: fake.NewSimpleClientset(
+				&apps_v1.Deployment{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Deployment",
+						APIVersion: "apps/v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "istiod",
+						Namespace: "istio-system",
+						Labels: map[string]string{
+							"app": "istiod",
+						},
+					},
+				},
+			),
+			expectedResult: true,
+		},
+	}
+
+	for _, test := range data {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+			client := Client{cs: test.clientset}
+			istioDetected, err := client.DetectIstio()
+			r.NoError(err)
+			r.Equal(test.expectedResult, istioDetected)
+		})
+	}
+}
+
 func TestClient_DetectTraefik(t *testing.T) {
 	// Test calling API for Traefik CRD
 	t.Run("Traefik CRD API is installed", func(t *testing.T) {
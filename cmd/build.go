@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubeshop/kusk-gen/project"
+	"github.com/kubeshop/kusk-gen/spec"
+)
+
+var (
+	projectFile string
+	buildEnv    string
+)
+
+func init() {
+	buildCmd := &cobra.Command{
+		Use:   "build",
+		Short: "Generates resources for all specs described in the project file",
+		Long: "Generates resources for all specs described in the project file (kusk.yaml by default), " +
+			"running the targets of every spec with its options and writing them to its output. " +
+			"When the project defines environments, every environment is built unless --env is set.",
+		Run: func(cmd *cobra.Command, args []string) {
+			p, err := project.Load(projectFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			builds, err := p.Builds(buildEnv)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			for _, build := range builds {
+				if err := runBuild(build); err != nil {
+					log.Fatal(fmt.Errorf("%s: %w", build, err))
+				}
+			}
+		},
+	}
+
+	buildCmd.Flags().StringVarP(
+		&projectFile,
+		"file",
+		"f",
+		project.DefaultFileName,
+		"path to the project file",
+	)
+
+	buildCmd.Flags().StringVar(
+		&buildEnv,
+		"env",
+		"",
+		"environment to build, all environments are built by default",
+	)
+
+	rootCmd.AddCommand(buildCmd)
+}
+
+func runBuild(build project.Build) error {
+	gens, err := getTargets(build.Targets)
+	if err != nil {
+		return err
+	}
+
+	apiSpec, err := spec.NewParser(spec.NewLoader()).Parse(build.SpecPath)
+	if err != nil {
+		return err
+	}
+
+	var overlays []*spec.Overlay
+
+	if build.OptionsFile != "" {
+		overlay, err := spec.LoadOverlay(build.OptionsFile)
+		if err != nil {
+			return err
+		}
+
+		overlays = append(overlays, overlay)
+	}
+
+	// project environments don't have to be defined in the spec
+	kuskExtensionOpts, err := spec.GetEnvironmentOptions(apiSpec, build.Env, overlays...)
+	if errors.Is(err, spec.ErrEnvironmentNotDefined) {
+		kuskExtensionOpts, err = spec.GetEnvironmentOptions(apiSpec, "", overlays...)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// flags are never set here, they only provide the defaults
+	defaults := pflag.NewFlagSet("kusk-gen", pflag.ContinueOnError)
+	addOptionFlags(defaults)
+	addAllGeneratorFlags(defaults)
+
+	opts, err := loadOptions(kuskExtensionOpts, defaults, build.Options...)
+	if err != nil {
+		return err
+	}
+
+	return generateAndWrite(gens, []input{{path: build.SpecPath, spec: apiSpec, opts: opts}}, outputOptions{
+		dir:               build.Output.Dir,
+		withKustomization: build.Output.Kustomization,
+		helmChart:         build.Output.HelmChart,
+		file:              build.Output.File,
+	})
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+var (
+	explainPath   string
+	explainMethod string
+)
+
+func init() {
+	explainCmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Shows the effective options of every operation and where they come from",
+		Long: "Shows the effective options of every operation in the spec, i.e. disabled, host, CORS, rate limits and timeouts, " +
+			"after merging the root, tag, path and operation level x-kusk extension and the flags. " +
+			"The source column tells whether each value comes from a flag, the root, tag, path or operation level x-kusk extension, " +
+			"or isn't set at all. Options from --env and --options-file are reported at the level they are set on.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(apiSpecPaths) != 1 {
+				log.Fatal("explain takes a single spec")
+			}
+
+			apiSpec, kuskExtensionOpts, err := parseSpec(apiSpecPaths[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			opts, err := loadOptions(kuskExtensionOpts, cmd.Flags())
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if err := explain(apiSpec, kuskExtensionOpts, opts, cmd.Flags().Changed); err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+
+	addInputFlags(explainCmd)
+
+	explainCmd.Flags().StringVar(
+		&explainPath,
+		"filter-path",
+		"",
+		"only explain the operations of this path, e.g. /books/{id}",
+	)
+
+	explainCmd.Flags().StringVar(
+		&explainMethod,
+		"filter-method",
+		"",
+		"only explain the operations with this method, e.g. get",
+	)
+
+	// accept the flags of the generators to explain the options they set
+	addOptionFlags(explainCmd.Flags())
+	addAllGeneratorFlags(explainCmd.Flags())
+
+	explainCmd.Flags().SortFlags = false
+
+	rootCmd.AddCommand(explainCmd)
+}
+
+// explain prints a table of the effective options of the operations matching --filter-path and --filter-method
+func explain(apiSpec *openapi3.T, kuskExtensionOpts, opts *options.Options, flagChanged func(name string) bool) error {
+	if explainPath != "" {
+		if _, ok := apiSpec.Paths[explainPath]; !ok {
+			return fmt.Errorf("path %s is not in the spec", explainPath)
+		}
+	}
+
+	method := strings.ToUpper(explainMethod)
+
+	paths := make([]string, 0, len(apiSpec.Paths))
+	for path := range apiSpec.Paths {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tOPTION\tVALUE\tSOURCE")
+
+	found := false
+
+	for _, path := range paths {
+		if explainPath != "" && path != explainPath {
+			continue
+		}
+
+		operations := apiSpec.Paths[path].Operations()
+
+		methods := make([]string, 0, len(operations))
+		for m := range operations {
+			methods = append(methods, m)
+		}
+
+		sort.Strings(methods)
+
+		for _, m := range methods {
+			if method != "" && m != method {
+				continue
+			}
+
+			explained, err := opts.Explain(kuskExtensionOpts, path, m, flagChanged)
+			if err != nil {
+				return err
+			}
+
+			for _, option := range explained {
+				value := option.Value
+				if value == "" {
+					value = "-"
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m, path, option.Name, value, option.Source)
+			}
+
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("no operations match the given path and method")
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubeshop/kusk-gen/generators"
+)
+
+var targets []string
+
+func init() {
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Runs several generators at once, e.g. --targets linkerd,ambassador2",
+		Long: "Runs several generators at once, parsing the OpenAPI spec and x-kusk extension only once. " +
+			"The resources are output in the order of the targets.",
+		Run: func(cmd *cobra.Command, args []string) {
+			gens, err := getTargets(targets)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			run(cmd, gens)
+		},
+	}
+
+	addGlobalFlags(generateCmd)
+
+	generateCmd.Flags().StringSliceVar(
+		&targets,
+		"targets",
+		nil,
+		fmt.Sprintf("comma-separated list of generators to run, one of %s", strings.Join(generatorNames(), ", ")),
+	)
+	generateCmd.MarkFlagRequired("targets")
+
+	addAllGeneratorFlags(generateCmd.Flags())
+
+	generateCmd.Flags().SortFlags = false
+
+	rootCmd.AddCommand(generateCmd)
+}
+
+// getTargets returns the generators with the given names, skipping duplicates
+func getTargets(names []string) ([]generators.Interface, error) {
+	var res []generators.Interface
+	seen := map[string]bool{}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if seen[name] {
+			continue
+		}
+
+		gen, ok := generators.Registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown target %q, available targets are %s", name, strings.Join(generatorNames(), ", "))
+		}
+
+		res = append(res, gen)
+		seen[name] = true
+	}
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no targets provided")
+	}
+
+	return res, nil
+}
+
+// addAllGeneratorFlags adds the flags of all generators, generators share flags with the same name
+func addAllGeneratorFlags(fs *pflag.FlagSet) {
+	for _, name := range generatorNames() {
+		fs.AddFlagSet(generators.Registry[name].Flags())
+	}
+}
+
+func generatorNames() []string {
+	names := make([]string, 0, len(generators.Registry))
+	for name := range generators.Registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -3,92 +3,372 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/confmap"
 	"github.com/knadh/koanf/providers/posflag"
 	"github.com/knadh/koanf/providers/structs"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/kubeshop/kusk-gen/generators"
 	_ "github.com/kubeshop/kusk-gen/generators/ambassador/v1"
 	_ "github.com/kubeshop/kusk-gen/generators/ambassador/v2"
+	_ "github.com/kubeshop/kusk-gen/generators/contour"
+	_ "github.com/kubeshop/kusk-gen/generators/envoy"
+	_ "github.com/kubeshop/kusk-gen/generators/gateway_api"
+	_ "github.com/kubeshop/kusk-gen/generators/istio"
+	_ "github.com/kubeshop/kusk-gen/generators/kong"
 	_ "github.com/kubeshop/kusk-gen/generators/linkerd"
 	_ "github.com/kubeshop/kusk-gen/generators/nginx_ingress"
 	_ "github.com/kubeshop/kusk-gen/generators/traefik"
 	"github.com/kubeshop/kusk-gen/options"
+	"github.com/kubeshop/kusk-gen/output"
+	"github.com/kubeshop/kusk-gen/routes"
 	"github.com/kubeshop/kusk-gen/spec"
 )
 
 var (
-	// this object initially gets filled with whatever we were able to find in x-kusk extension
-	// each flag can then override settings
-	k = koanf.New(".")
+	apiSpecPaths []string
+	mergeName    string
+	environment  string
+	optionsFile  string
 
-	apiSpecPath string
+	outDir            string
+	withKustomization bool
+	helmChart         string
 )
 
-func getOptions() (*options.Options, error) {
+// outputOptions tell where to write the generated resources, stdout if none are set
+type outputOptions struct {
+	dir               string
+	withKustomization bool
+	helmChart         string
+	file              string
+
+	// mergeName is the name of the resources merging the routes of several specs
+	mergeName string
+}
+
+// loadOptions merges the options found in x-kusk extension with the overrides, in order of precedence,
+// and the flags. Flags set by the user take precedence over everything, default flag values only fill in the gaps.
+func loadOptions(kuskExtensionOpts *options.Options, flags *pflag.FlagSet, overrides ...map[string]interface{}) (*options.Options, error) {
+	k := koanf.New(".")
+
+	// populate koanf object with the extension content
+	if err := k.Load(structs.Provider(*kuskExtensionOpts, "yaml"), nil); err != nil {
+		return nil, err
+	}
+
+	// overrides are loaded as written, structs would drop the false and zero values set explicitly,
+	// no delimiter keeps dotted keys, e.g. of labels, in one piece
+	for _, override := range overrides {
+		if err := k.Load(confmap.Provider(override, ""), nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// override koanf options with user-provided flags,
+	// map flags are merged separately as koanf can't decode them
+	if err := k.Load(posflag.Provider(withoutMapFlags(flags), ".", k), nil); err != nil {
+		return nil, err
+	}
+
 	var res options.Options
 
-	err := k.UnmarshalWithConf("", &res, koanf.UnmarshalConf{Tag: "yaml"})
-	if err != nil {
+	decoderConfig := &mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.ComposeDecodeHookFunc(durationHook, mapstructure.StringToTimeDurationHookFunc()),
+		WeaklyTypedInput: true,
+		Result:           &res,
+	}
+
+	if err := k.UnmarshalWithConf("", &res, koanf.UnmarshalConf{Tag: "yaml", DecoderConfig: decoderConfig}); err != nil {
 		return nil, fmt.Errorf("failed to decode options: %w", err)
 	}
 
+	res.Metadata.Labels = mergeMapFlag(flags, "metadata.labels", res.Metadata.Labels)
+	res.Metadata.Annotations = mergeMapFlag(flags, "metadata.annotations", res.Metadata.Annotations)
+
+	res.PathSubOptions = kuskExtensionOpts.PathSubOptions
+	res.OperationSubOptions = kuskExtensionOpts.OperationSubOptions
+	res.TagSubOptions = kuskExtensionOpts.TagSubOptions
+	res.OperationTags = kuskExtensionOpts.OperationTags
+
 	return &res, nil
 }
 
-func init() {
-	addGenerator := func(gen generators.Interface) {
-		cmd := &cobra.Command{
-			Use:   gen.Cmd(),
-			Short: gen.ShortDescription(),
-			Long:  gen.LongDescription(),
-			Run: func(cmd *cobra.Command, args []string) {
-				if apiSpecPath == "" {
-					log.Fatal(fmt.Errorf("no openapi or swagger definition provided"))
-				}
+// input is a parsed spec along with its options
+type input struct {
+	path string
+	spec *openapi3.T
+	opts *options.Options
+}
 
-				// parse OpenAPI spec
-				apiSpec, err := spec.NewParser(openapi3.NewLoader()).Parse(apiSpecPath)
-				if err != nil {
-					log.Fatal(err)
-				}
+// run generates the resources with the given generators, parsing the specs and x-kusk extensions once.
+// The output of each generator follows the previous one in the given order.
+func run(cmd *cobra.Command, gens []generators.Interface) {
+	inputs, err := parseSpecs(cmd.Flags())
+	if err != nil {
+		log.Fatal(err)
+	}
 
-				// parse x-kusk top-level extension
-				kuskExtensionOpts, err := spec.GetOptions(apiSpec)
-				if err != nil {
-					log.Fatal(err)
-				}
+	err = generateAndWrite(gens, inputs, outputOptions{
+		dir:               outDir,
+		withKustomization: withKustomization,
+		helmChart:         helmChart,
+		mergeName:         mergeName,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
 
-				// populate koanf object with the extension content
-				err = k.Load(structs.Provider(*kuskExtensionOpts, "yaml"), nil)
-				if err != nil {
-					log.Fatal(err)
-				}
+// parseSpecs parses the specs given with --in along with their options, see parseSpec,
+// the flags apply to all of them
+func parseSpecs(flags *pflag.FlagSet) ([]input, error) {
+	if len(apiSpecPaths) == 0 {
+		return nil, fmt.Errorf("no openapi or swagger definition provided")
+	}
 
-				// override koanf options with user-provided flags
-				err = k.Load(posflag.Provider(cmd.Flags(), ".", k), nil)
-				if err != nil {
-					log.Fatal(err)
-				}
+	if len(apiSpecPaths) > 1 && optionsFile != "" {
+		return nil, fmt.Errorf("--options-file can't be used with several specs, set the options in x-kusk extension of each spec")
+	}
 
-				// fetch merged options
-				opts, err := getOptions()
-				if err != nil {
-					log.Fatal(err)
-				}
+	inputs := make([]input, 0, len(apiSpecPaths))
+
+	for _, path := range apiSpecPaths {
+		apiSpec, kuskExtensionOpts, err := parseSpec(path)
+		if err != nil {
+			return nil, wrapSpecError(path, len(apiSpecPaths), err)
+		}
+
+		opts, err := loadOptions(kuskExtensionOpts, flags)
+		if err != nil {
+			return nil, wrapSpecError(path, len(apiSpecPaths), err)
+		}
+
+		inputs = append(inputs, input{path: path, spec: apiSpec, opts: opts})
+	}
 
-				opts.PathSubOptions = kuskExtensionOpts.PathSubOptions
-				opts.OperationSubOptions = kuskExtensionOpts.OperationSubOptions
+	return inputs, nil
+}
+
+// wrapSpecError prefixes the error with the spec path when there are several specs
+func wrapSpecError(path string, specs int, err error) error {
+	if specs > 1 {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return err
+}
+
+// parseSpec parses the spec at path and its x-kusk extension,
+// merging the environment given with --env and the options file given with --options-file
+func parseSpec(path string) (*openapi3.T, *options.Options, error) {
+	// parse OpenAPI spec
+	apiSpec, err := spec.NewParser(spec.NewLoader()).Parse(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var overlays []*spec.Overlay
+
+	if optionsFile != "" {
+		overlay, err := spec.LoadOverlay(optionsFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		overlays = append(overlays, overlay)
+	}
+
+	// parse x-kusk top-level extension
+	kuskExtensionOpts, err := spec.GetEnvironmentOptions(apiSpec, environment, overlays...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return apiSpec, kuskExtensionOpts, nil
+}
+
+// generateAndWrite generates the resources of the inputs with the given generators and writes them according to out.
+// The resources generated for several inputs are merged into one routing configuration by generators supporting it.
+func generateAndWrite(gens []generators.Interface, inputs []input, out outputOptions) error {
+	if out.dir != "" && out.helmChart != "" {
+		return fmt.Errorf("--out-dir and --helm-chart can't be used together")
+	}
+
+	if len(inputs) > 1 && out.helmChart != "" {
+		return fmt.Errorf("--helm-chart can't be used with several specs")
+	}
+
+	conflictWarnings, err := findConflicts(inputs)
+	if err != nil {
+		return err
+	}
+
+	// generateEach returns the result of every generator, merged for all the inputs,
+	// each generator gets its own copy of the options as it fills in the defaults
+	generateEach := func(inputs []input) ([]*generators.Result, error) {
+		results := make([]*generators.Result, 0, len(gens))
+
+		for _, gen := range gens {
+			inputResults := make([]*generators.Result, 0, len(inputs))
 
-				res, err := gen.Generate(opts, apiSpec)
+			for _, in := range inputs {
+				genOpts := *in.opts
+
+				res, err := generators.GenerateObjects(gen, &genOpts, in.spec)
 				if err != nil {
-					log.Fatal(err)
+					err = wrapSpecError(in.path, len(inputs), err)
+
+					if len(gens) > 1 {
+						return nil, fmt.Errorf("%s: %w", gen.Cmd(), err)
+					}
+
+					return nil, err
 				}
 
-				fmt.Println(res)
+				inputResults = append(inputResults, res)
+			}
+
+			// metadata is applied to the resources of each spec before they are merged,
+			// so the merged resources are named like the other resources of the first spec
+			mergeName := out.mergeName
+			if mergeName != "" {
+				mergeName = inputs[0].opts.Metadata.ResourceName(mergeName)
+			}
+
+			res, err := generators.Merge(gen, mergeName, inputResults)
+			if err != nil {
+				return nil, err
+			}
+
+			results = append(results, res)
+		}
+
+		return results, nil
+	}
+
+	results, err := generateEach(inputs)
+	if err != nil {
+		return err
+	}
+
+	res := generators.Combine(results)
+	res.Warnings = append(conflictWarnings, res.Warnings...)
+
+	// options for the outputs, every input shares the flags
+	opts := inputs[0].opts
+
+	if out.helmChart != "" {
+		generate := func(opts *options.Options) (*generators.Result, error) {
+			results, err := generateEach([]input{{path: inputs[0].path, spec: inputs[0].spec, opts: opts}})
+			if err != nil {
+				return nil, err
+			}
+
+			return generators.Combine(results), nil
+		}
+
+		err := output.WriteChart(out.helmChart, res, opts, inputs[0].spec.Info, generate)
+		res.LogWarnings()
+
+		return err
+	}
+
+	res.LogWarnings()
+
+	if out.dir != "" {
+		// every file is marshaled like the output of the generator its resources were generated by
+		generatedBy := map[*unstructured.Unstructured]generators.Interface{}
+		for i, gen := range gens {
+			for _, obj := range results[i].Objects {
+				generatedBy[obj] = gen
+			}
+		}
+
+		marshal := func(res *generators.Result) (string, error) {
+			return generators.Marshal(generatedBy[res.Objects[0]], opts, res)
+		}
+
+		return output.WriteDir(out.dir, res, marshal, out.withKustomization)
+	}
+
+	var builder strings.Builder
+
+	for i, gen := range gens {
+		out, err := generators.Marshal(gen, opts, results[i])
+		if err != nil {
+			return err
+		}
+
+		// separate the outputs of generators not starting with a document separator, e.g. Envoy
+		if i > 0 && !strings.HasPrefix(out, "---") {
+			builder.WriteString("---\n")
+		}
+
+		builder.WriteString(out)
+		builder.WriteString("\n")
+	}
+
+	if out.file != "" {
+		return output.WriteFile(out.file, builder.String())
+	}
+
+	fmt.Print(builder.String())
+
+	return nil
+}
+
+// findConflicts returns an error listing the operations resolving to the same route,
+// and warnings for the regex routes matching literal paths, which they may shadow depending on the target
+func findConflicts(inputs []input) ([]string, error) {
+	specs := make([]routes.Spec, 0, len(inputs))
+
+	for _, in := range inputs {
+		spec := routes.Spec{Spec: in.spec, Options: in.opts}
+
+		// name the specs only when there are several of them
+		if len(inputs) > 1 {
+			spec.Name = in.path
+		}
+
+		specs = append(specs, spec)
+	}
+
+	var warnings, errs []string
+
+	for _, conflict := range routes.Find(specs) {
+		if conflict.Shadowed {
+			warnings = append(warnings, conflict.String())
+			continue
+		}
+
+		errs = append(errs, conflict.String())
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("conflicting routes: %s", strings.Join(errs, "; "))
+	}
+
+	return warnings, nil
+}
+
+func init() {
+	addGenerator := func(gen generators.Interface) {
+		cmd := &cobra.Command{
+			Use:   gen.Cmd(),
+			Short: gen.ShortDescription(),
+			Long:  gen.LongDescription(),
+			Run: func(cmd *cobra.Command, args []string) {
+				run(cmd, []generators.Interface{gen})
 			},
 		}
 
@@ -107,37 +387,172 @@ func init() {
 }
 
 func addGlobalFlags(cmd *cobra.Command) {
+	addInputFlags(cmd)
+
+	cmd.Flags().StringVar(
+		&outDir,
+		"out-dir",
+		"",
+		"write each generated resource to its own file in this directory instead of stdout, removing stale files from previous runs",
+	)
+
+	cmd.Flags().BoolVar(
+		&withKustomization,
+		"kustomization",
+		false,
+		"write a kustomization.yaml listing the generated resources, used with --out-dir",
+	)
+
+	cmd.Flags().StringVar(
+		&helmChart,
+		"helm-chart",
+		"",
+		"package the generated resources into a Helm chart in this directory, lifting host, namespace, service and rate limit options into values.yaml",
+	)
+
+	cmd.Flags().StringVar(
+		&mergeName,
+		"merge-name",
+		"",
+		"name of the resources merging the routes of several specs given with --in, e.g. public-api. Defaults to the name generated for the first spec",
+	)
+
+	addOptionFlags(cmd.Flags())
+}
+
+// addInputFlags adds the flags telling which spec to read and which x-kusk options to merge into it
+func addInputFlags(cmd *cobra.Command) {
 	// add global required flags
-	cmd.Flags().StringVarP(
-		&apiSpecPath,
+	cmd.Flags().StringArrayVarP(
+		&apiSpecPaths,
 		"in",
 		"i",
-		"",
-		"file path to api spec file to generate mappings from. e.g. --in apispec.yaml",
+		nil,
+		"file path or URL to api spec file to generate mappings from, - to read it from stdin. e.g. --in apispec.yaml. "+
+			"Repeat to generate one routing configuration for several specs, each with its own x-kusk options",
 	)
 	cmd.MarkFlagRequired("in")
 
-	cmd.Flags().String(
+	cmd.Flags().StringVar(
+		&environment,
+		"env",
+		"",
+		"environment profile from x-kusk extension to merge over the base options, e.g. prod",
+	)
+
+	cmd.Flags().StringVar(
+		&optionsFile,
+		"options-file",
+		"",
+		"file with x-kusk options to merge over the ones in the spec, with path and operation level options under paths",
+	)
+}
+
+// addOptionFlags adds the flags of options shared by all generators
+func addOptionFlags(fs *pflag.FlagSet) {
+	fs.StringToString(
+		"metadata.labels",
+		nil,
+		"labels to add to every generated resource, e.g. team=books",
+	)
+
+	fs.StringToString(
+		"metadata.annotations",
+		nil,
+		"annotations to add to every generated resource",
+	)
+
+	fs.String(
+		"metadata.name_prefix",
+		"",
+		"prefix to prepend to the name of every generated resource",
+	)
+
+	fs.String(
+		"metadata.name_suffix",
+		"",
+		"suffix to append to the name of every generated resource",
+	)
+
+	fs.Bool(
+		"metadata.ownership",
+		false,
+		"add the app.kubernetes.io/managed-by: kusk-gen label and the spec title and version labels to every generated resource",
+	)
+
+	fs.String(
 		"namespace",
 		"default",
 		"namespace for generated resources",
 	)
 
-	cmd.Flags().String(
+	fs.String(
 		"service.name",
 		"",
 		"target Service name",
 	)
 
-	cmd.Flags().String(
+	fs.String(
 		"service.namespace",
 		"default",
 		"namespace containing the target Service",
 	)
 
-	cmd.Flags().Int32(
+	fs.Int32(
 		"service.port",
 		80,
 		"target Service port",
 	)
 }
+
+// durationHook decodes timeouts given as flags, which koanf loads as strings, e.g. 1500ms,
+// and as numbers of seconds in project file overrides
+func durationHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(options.Duration(0)) {
+		return data, nil
+	}
+
+	switch from.Kind() {
+	case reflect.String:
+		return options.ParseDuration(data.(string))
+	case reflect.Float64:
+		return options.Duration(data.(float64) * float64(time.Second)), nil
+	default:
+		return data, nil
+	}
+}
+
+// withoutMapFlags returns the flag set without map flags, which koanf would load as plain strings
+func withoutMapFlags(flags *pflag.FlagSet) *pflag.FlagSet {
+	res := pflag.NewFlagSet("kusk-gen", pflag.ContinueOnError)
+
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Value.Type() != "stringToString" {
+			res.AddFlag(f)
+		}
+	})
+
+	return res
+}
+
+// mergeMapFlag merges the values of a map flag into the values set in x-kusk, flag values take precedence
+func mergeMapFlag(flags *pflag.FlagSet, name string, values map[string]string) map[string]string {
+	if !flags.Changed(name) {
+		return values
+	}
+
+	flagValues, err := flags.GetStringToString(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if values == nil {
+		values = map[string]string{}
+	}
+
+	for key, value := range flagValues {
+		values[key] = value
+	}
+
+	return values
+}
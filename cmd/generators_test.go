@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+func TestLoadOptionsOverrides(t *testing.T) {
+	r := require.New(t)
+
+	kuskExtensionOpts := &options.Options{
+		Disabled: true,
+		Host:     "books.example.org",
+		Path: options.PathOptions{
+			Base:   "/api",
+			Prefix: true,
+		},
+		RateLimits: options.RateLimitOptions{RPS: 100, Burst: 200},
+	}
+
+	flags := pflag.NewFlagSet("kusk-gen", pflag.ContinueOnError)
+	addOptionFlags(flags)
+
+	opts, err := loadOptions(kuskExtensionOpts, flags,
+		map[string]interface{}{
+			"disabled":    false,
+			"path":        map[string]interface{}{"prefix": false},
+			"rate_limits": map[string]interface{}{"burst": float64(0)},
+		},
+		map[string]interface{}{
+			"timeouts": map[string]interface{}{"request_timeout": float64(5)},
+		},
+	)
+	r.NoError(err)
+
+	r.False(opts.Disabled)
+	r.False(opts.Path.Prefix)
+	r.Equal("/api", opts.Path.Base)
+	r.Equal("books.example.org", opts.Host)
+	r.Equal(options.RateLimitOptions{RPS: 100}, opts.RateLimits)
+	r.Equal(options.Duration(5*time.Second), opts.Timeouts.RequestTimeout)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/spec"
+)
+
+var validateSpecPath string
+
+func init() {
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validates x-kusk extension of the spec against its JSON Schema",
+		Long: "Validates x-kusk extension on every level of the spec, and the options file if given, against its JSON Schema. " +
+			"Unknown options, values of wrong types and invalid values are reported with the JSON pointer of the offending node, " +
+			"e.g. /paths/~1books/get/x-kusk/timeout: unknown option. " +
+			"CORS, rate limit and timeout options are then checked on every level, e.g. that burst isn't less than rps, " +
+			"and an idle timeout less than the request timeout is warned about. " +
+			"Exits with a non-zero status if any problem is found.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := validate(); err != nil {
+				var extensionErrs spec.ExtensionErrors
+				if !errors.As(err, &extensionErrs) {
+					log.Fatal(err)
+				}
+
+				for _, extensionErr := range extensionErrs {
+					if extensionErr.File == "" {
+						extensionErr.File = validateSpecPath
+					}
+
+					fmt.Fprintln(os.Stderr, extensionErr)
+				}
+
+				os.Exit(1)
+			}
+		},
+	}
+
+	validateCmd.Flags().StringVarP(
+		&validateSpecPath,
+		"in",
+		"i",
+		"",
+		"file path or URL to api spec file to validate, - to read it from stdin. e.g. --in apispec.yaml",
+	)
+	validateCmd.MarkFlagRequired("in")
+
+	validateCmd.Flags().StringVar(
+		&optionsFile,
+		"options-file",
+		"",
+		"file with x-kusk options to validate along with the spec",
+	)
+
+	rootCmd.AddCommand(validateCmd)
+
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Prints the JSON Schema of x-kusk extension",
+		Run: func(cmd *cobra.Command, args []string) {
+			b, err := spec.JSONSchema()
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			fmt.Println(string(b))
+		},
+	}
+
+	rootCmd.AddCommand(schemaCmd)
+}
+
+// validate validates x-kusk extension of the spec given with --in and the options file given with --options-file
+func validate() error {
+	apiSpec, err := spec.NewParser(spec.NewLoader()).Parse(validateSpecPath)
+	if err != nil {
+		return err
+	}
+
+	var overlays []*spec.Overlay
+
+	if optionsFile != "" {
+		overlay, err := spec.LoadOverlay(optionsFile)
+		if err != nil {
+			return err
+		}
+
+		overlays = append(overlays, overlay)
+	}
+
+	if err := spec.ValidateExtension(apiSpec, overlays...); err != nil {
+		return err
+	}
+
+	// environment profiles and options file paths are checked when the options are parsed
+	opts, err := spec.GetEnvironmentOptions(apiSpec, "", overlays...)
+	if err != nil {
+		return err
+	}
+
+	if err := opts.ValidateLevels(); err != nil {
+		return err
+	}
+
+	(&generators.Result{Warnings: opts.Warnings()}).LogWarnings()
+
+	return nil
+}
@@ -4,7 +4,6 @@ import (
 	"log"
 	"os"
 
-	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
@@ -24,8 +23,12 @@ func init() {
 				log.Fatal("the wizard is only supported in an interactive context i.e. TTY")
 			}
 
+			if apiSpecPath == spec.StdinPath {
+				log.Fatal("the wizard reads the answers from stdin, the spec must be given as a file path or URL")
+			}
+
 			// parse OpenAPI spec
-			apiSpec, err := spec.NewParser(openapi3.NewLoader()).Parse(apiSpecPath)
+			apiSpec, err := spec.NewParser(spec.NewLoader()).Parse(apiSpecPath)
 			if err != nil {
 				log.Fatal(err)
 			}
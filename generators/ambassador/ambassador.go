@@ -12,13 +12,12 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/spf13/pflag"
 
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/generators/pathparams"
 	"github.com/kubeshop/kusk-gen/options"
 )
 
-var (
-	rePathSymbols       = regexp.MustCompile(`[/{}]`)
-	reDuplicateNewlines = regexp.MustCompile(`\s*\n+`)
-)
+var rePathSymbols = regexp.MustCompile(`[/{}]`)
 
 type AbstractGenerator struct {
 	MappingTemplate   *template.Template
@@ -64,16 +63,16 @@ func (*AbstractGenerator) Flags() *pflag.FlagSet {
 		"request per second burst",
 	)
 
-	fs.Uint32(
+	fs.Var(
+		new(options.Duration),
 		"timeouts.request_timeout",
-		0,
-		"total request timeout (seconds)",
+		"total request timeout, in seconds or as a duration, e.g. 1500ms",
 	)
 
-	fs.Uint32(
+	fs.Var(
+		new(options.Duration),
 		"timeouts.idle_timeout",
-		0,
-		"idle connection timeout (seconds)",
+		"idle connection timeout, in seconds or as a duration, e.g. 2m",
 	)
 
 	fs.String(
@@ -86,8 +85,21 @@ func (*AbstractGenerator) Flags() *pflag.FlagSet {
 }
 
 func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	res, err := generators.GenerateObjects(a, opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	res.LogWarnings()
+
+	return res.YAML()
+}
+
+// GenerateObjects renders Mappings and RateLimits through the templates
+// and decodes the rendered resources
+func (a *AbstractGenerator) GenerateObjects(opts *options.Options, spec *openapi3.T) (*generators.Result, error) {
 	if err := opts.FillDefaultsAndValidate(); err != nil {
-		return "", fmt.Errorf("failed to validate options: %w", err)
+		return nil, fmt.Errorf("failed to validate options: %w", err)
 	}
 
 	var mappings []mappingTemplateData
@@ -99,26 +111,15 @@ func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (s
 		// generate a mapping for each operation
 		basePath := strings.TrimSuffix(opts.Path.Base, "/")
 
-		host := opts.Host
-
 		for path, pathItem := range spec.Paths {
-			pathSubOptions := opts.PathSubOptions[path]
-
-			if pathSubOptions.Host != "" && pathSubOptions.Host != host {
-				host = pathSubOptions.Host
-			}
-
 			for method, operation := range pathItem.Operations() {
 				if opts.IsOperationDisabled(path, method) {
 					continue
 				}
 
-				opSubOptions := opts.OperationSubOptions[method+path]
-				if opSubOptions.Host != "" && opSubOptions.Host != host {
-					host = opSubOptions.Host
-				}
+				host := opts.GetHost(path, method)
 
-				mappingPath, regex := generateMappingPath(path, operation)
+				mappingPath, regex := GenerateMappingPath(path, pathItem, operation)
 				mappingName := generateMappingName(opts.Service.Name, method, path, operation)
 
 				var pathRewrite string
@@ -206,8 +207,8 @@ func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (s
 
 				// if final timeout options are not empty, include them
 				if !reflect.DeepEqual(options.TimeoutOptions{}, timeoutOpts) {
-					op.RequestTimeout = timeoutOpts.RequestTimeout * 1000
-					op.IdleTimeout = timeoutOpts.IdleTimeout * 1000
+					op.RequestTimeout = uint32(timeoutOpts.RequestTimeout.Milliseconds())
+					op.IdleTimeout = uint32(timeoutOpts.IdleTimeout.Milliseconds())
 				}
 
 				mappings = append(mappings, op)
@@ -221,8 +222,8 @@ func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (s
 			BasePath:         opts.Path.Base,
 			TrimPrefix:       opts.Path.TrimPrefix,
 			PathRewrite:      opts.Path.Rewrite,
-			RequestTimeout:   opts.Timeouts.RequestTimeout * 1000,
-			IdleTimeout:      opts.Timeouts.IdleTimeout * 1000,
+			RequestTimeout:   uint32(opts.Timeouts.RequestTimeout.Milliseconds()),
+			IdleTimeout:      uint32(opts.Timeouts.IdleTimeout.Milliseconds()),
 			Host:             opts.Host,
 		}
 
@@ -290,54 +291,38 @@ func (a *AbstractGenerator) Generate(opts *options.Options, spec *openapi3.T) (s
 	var buf bytes.Buffer
 
 	if err := a.MappingTemplate.Execute(&buf, mappings); err != nil {
-		return "", fmt.Errorf("failed to execute mapping template: %w", err)
+		return nil, fmt.Errorf("failed to execute mapping template: %w", err)
 	}
 
 	if err := a.RateLimitTemplate.Execute(&buf, rateLimits); err != nil {
-		return "", fmt.Errorf("failed to execute rate limit template: %w", err)
+		return nil, fmt.Errorf("failed to execute rate limit template: %w", err)
 	}
 
-	res := buf.String()
+	res := &generators.Result{}
+
+	if err := res.AddYAML(buf.Bytes()); err != nil {
+		return nil, err
+	}
 
-	return reDuplicateNewlines.ReplaceAllString(res, "\n"), nil
+	return res, nil
 }
 
-// generateMappingPath returns the final pattern that should go to mapping
+// GenerateMappingPath returns the final pattern that should go to mapping
 // and whether the regex should be used
-func generateMappingPath(path string, op *openapi3.Operation) (string, bool) {
-	containsPathParameter := false
-
-	for _, param := range op.Parameters {
-		if param.Value.In == "path" {
-			containsPathParameter = true
-			break
-		}
-	}
-
-	if !containsPathParameter {
+func GenerateMappingPath(path string, pathItem *openapi3.PathItem, op *openapi3.Operation) (string, bool) {
+	params := pathparams.Find(pathItem, op)
+	if len(params) == 0 {
 		return path, false
 	}
 
-	// replace each parameter with appropriate regex
-	for _, param := range op.Parameters {
-		if param.Value.In != "path" {
-			continue
-		}
-
-		// the regex evaluation for mapping routes is actually done
-		// within Envoy, which uses ECMA-262 regex grammar
-		// https://www.envoyproxy.io/docs/envoy/v1.5.0/api-v1/route_config/route#route
-		// https://en.cppreference.com/w/cpp/regex/ecmascript
-		// https://www.getambassador.io/docs/edge-stack/latest/topics/using/rewrites/#regex_rewrite
-
-		replaceWith := `([a-zA-Z0-9]*)`
-
-		oldParam := "{" + param.Value.Name + "}"
-
-		path = strings.ReplaceAll(path, oldParam, replaceWith)
-	}
-
-	return path, true
+	// replace each parameter with the regex derived from its schema,
+	// the regex evaluation for mapping routes is actually done
+	// within Envoy, which uses RE2 regex grammar for safe regex
+	// https://www.envoyproxy.io/docs/envoy/latest/api-v3/type/matcher/v3/regex.proto
+	// https://www.getambassador.io/docs/edge-stack/latest/topics/using/rewrites/#regex_rewrite
+	return pathparams.Replace(path, params, func(_, pattern string) string {
+		return "(" + pattern + ")"
+	}), true
 }
 
 func generateMappingName(serviceName, method, path string, operation *openapi3.Operation) string {
@@ -389,45 +374,13 @@ func shouldSplit(opts *options.Options, spec *openapi3.T) bool {
 	}
 
 	for path, pathItem := range spec.Paths {
-		if pathSubOptions, ok := opts.PathSubOptions[path]; ok {
-			// a path has non-zero, different from global scope CORS options
-			if !reflect.DeepEqual(options.CORSOptions{}, pathSubOptions.CORS) &&
-				!reflect.DeepEqual(opts.CORS, pathSubOptions.CORS) {
-				return true
-			}
-
-			// a path has non-zero, different from global scope rate limits options
-			if !reflect.DeepEqual(options.RateLimitOptions{}, pathSubOptions.RateLimits) &&
-				!reflect.DeepEqual(opts.RateLimits, pathSubOptions.RateLimits) {
-				return true
-			}
-
-			// a path has non-zero, different from global scope timeouts options
-			if !reflect.DeepEqual(options.TimeoutOptions{}, pathSubOptions.Timeouts) &&
-				!reflect.DeepEqual(opts.Timeouts, pathSubOptions.Timeouts) {
-				return true
-			}
-		}
-
 		for method := range pathItem.Operations() {
-			if opSubOptions, ok := opts.OperationSubOptions[method+path]; ok {
-				// an operation has non-zero, different from global CORS options
-				if !reflect.DeepEqual(options.CORSOptions{}, opSubOptions.CORS) &&
-					!reflect.DeepEqual(opts.CORS, opSubOptions.CORS) {
-					return true
-				}
-
-				// an operation has non-zero, different from global scope rate limits options
-				if !reflect.DeepEqual(options.RateLimitOptions{}, opSubOptions.RateLimits) &&
-					!reflect.DeepEqual(opts.RateLimits, opSubOptions.RateLimits) {
-					return true
-				}
-
-				// an operation has non-zero, different from global timeouts options
-				if !reflect.DeepEqual(options.TimeoutOptions{}, opSubOptions.Timeouts) &&
-					!reflect.DeepEqual(opts.Timeouts, opSubOptions.Timeouts) {
-					return true
-				}
+			// an operation has CORS, rate limits or timeouts options different from global scope ones,
+			// either set on the operation itself or inherited from the path
+			if !reflect.DeepEqual(opts.CORS, opts.GetCORSOpts(path, method)) ||
+				!reflect.DeepEqual(opts.RateLimits, opts.GetRateLimitOpts(path, method)) ||
+				!reflect.DeepEqual(opts.Timeouts, opts.GetTimeoutOpts(path, method)) {
+				return true
 			}
 		}
 	}
@@ -60,3 +60,7 @@ func (g *Generator) Flags() *pflag.FlagSet {
 func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
 	return g.AbstractGenerator.Generate(opts, spec)
 }
+
+func (g *Generator) GenerateObjects(opts *options.Options, spec *openapi3.T) (*generators.Result, error) {
+	return g.AbstractGenerator.GenerateObjects(opts, spec)
+}
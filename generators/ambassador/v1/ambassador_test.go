@@ -3,6 +3,7 @@ package v1
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/require"
@@ -54,18 +55,17 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   method: PUT
-  service: petstore.default:80
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -103,18 +103,17 @@ spec:
   }
 }
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   method: PUT
-  service: petstore.default:80
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -144,18 +143,17 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: amb
 spec:
-  prefix: "/pet"
   method: PUT
-  service: petstore.default:80
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -193,19 +191,18 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   method: POST
-  service: petstore.default:80
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -242,19 +239,18 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-postpetpetiduploadimage
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   method: POST
-  service: petstore.default:80
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -291,19 +287,18 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-postpetpetiduploadimage
   namespace: default
 spec:
-  prefix: "/api/v3/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   method: POST
-  service: petstore.default:80
+  prefix: /api/v3/pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -346,17 +341,16 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore
   namespace: default
 spec:
-  prefix: "/api/v3"
-  service: petstore.default:80
+  prefix: /api/v3
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -393,21 +387,20 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-postpetpetiduploadimage
   namespace: default
 spec:
-  prefix: "/petstore/api/v3/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   method: POST
-  service: petstore.default:80
+  prefix: /petstore/api/v3/pet/([0-9]+)/uploadImage
+  prefix_regex: true
   regex_rewrite:
-    pattern: '/petstore(.*)'
-    substitution: '\1'
+    pattern: /petstore(.*)
+    substitution: \1
+  service: petstore.default:80
 `,
 		},
 		{
@@ -507,18 +500,17 @@ definitions:
       message:
         type: string
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-createpets
   namespace: default
 spec:
-  prefix: "/pets"
   method: POST
-  service: petstore.default:80
+  prefix: /pets
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -526,10 +518,10 @@ metadata:
   name: petstore-listpets
   namespace: default
 spec:
-  prefix: "/pets"
   method: GET
-  service: petstore.default:80
+  prefix: /pets
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -537,11 +529,11 @@ metadata:
   name: petstore-showpetbyid
   namespace: default
 spec:
-  prefix: "/pets/([a-zA-Z0-9]*)"
-  prefix_regex: true
   method: GET
-  service: petstore.default:80
+  prefix: /pets/([^/]+)
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -684,18 +676,17 @@ spec:
   }
 }
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-createpets
   namespace: default
 spec:
-  prefix: "/pets"
   method: POST
-  service: petstore.default:80
+  prefix: /pets
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -703,10 +694,10 @@ metadata:
   name: petstore-listpets
   namespace: default
 spec:
-  prefix: "/pets"
   method: GET
-  service: petstore.default:80
+  prefix: /pets
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -714,11 +705,11 @@ metadata:
   name: petstore-showpetbyid
   namespace: default
 spec:
-  prefix: "/pets/([a-zA-Z0-9]*)"
-  prefix_regex: true
   method: GET
-  service: petstore.default:80
+  prefix: /pets/([^/]+)
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -749,18 +740,17 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   method: PUT
-  service: petstore.default:443
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:443
 `,
 		},
 		{
@@ -791,18 +781,17 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   method: PUT
-  service: petstore.default:80
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -847,19 +836,18 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   method: POST
-  service: petstore.default:80
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -904,19 +892,18 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   method: POST
-  service: petstore.default:80
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -962,24 +949,23 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore
   namespace: default
 spec:
-  prefix: "/"
-  service: petstore.default:80
-  rewrite: ""
   cors:
-    origins: http://foo.example,http://bar.example
-    methods: POST,GET,OPTIONS
-    headers: Content-Type
-    exposed_headers: X-Custom-Header,X-Other-Custom-Header
     credentials: false
+    exposed_headers: X-Custom-Header,X-Other-Custom-Header
+    headers: Content-Type
     max_age: "120"
+    methods: POST,GET,OPTIONS
+    origins: http://foo.example,http://bar.example
+  prefix: /
+  rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -999,7 +985,7 @@ spec:
 					MaxAge:        120,
 				},
 				Timeouts: options.TimeoutOptions{
-					RequestTimeout: 5,
+					RequestTimeout: options.Duration(5 * time.Second),
 				},
 				PathSubOptions: map[string]options.SubOptions{
 					"/pet": {
@@ -1040,25 +1026,24 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
-  method: PUT
-  service: petstore.default:80
-  rewrite: ""
   cors:
-    origins: http://bar.example
-    methods: POST
-    headers: Content-Type
-    exposed_headers: X-Custom-Header,X-Other-Custom-Header
     credentials: false
+    exposed_headers: X-Custom-Header,X-Other-Custom-Header
+    headers: Content-Type
     max_age: "240"
+    methods: POST
+    origins: http://bar.example
+  method: PUT
+  prefix: /pet
+  rewrite: ""
+  service: petstore.default:80
   timeout_ms: 5000
 ---
 apiVersion: getambassador.io/v2
@@ -1067,18 +1052,18 @@ metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
-  method: POST
-  service: petstore.default:80
-  rewrite: ""
   cors:
-    origins: http://foo.example,http://bar.example
-    methods: POST,GET,OPTIONS
-    headers: Content-Type
-    exposed_headers: X-Custom-Header,X-Other-Custom-Header
     credentials: false
+    exposed_headers: X-Custom-Header,X-Other-Custom-Header
+    headers: Content-Type
     max_age: "120"
+    methods: POST,GET,OPTIONS
+    origins: http://foo.example,http://bar.example
+  method: POST
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
+  rewrite: ""
+  service: petstore.default:80
   timeout_ms: 5000
 `,
 		},
@@ -1091,8 +1076,8 @@ spec:
 					Name:      "petstore",
 				},
 				Timeouts: options.TimeoutOptions{
-					RequestTimeout: 42,
-					IdleTimeout:    43,
+					RequestTimeout: options.Duration(42 * time.Second),
+					IdleTimeout:    options.Duration(43 * time.Second),
 				},
 			},
 			spec: `
@@ -1121,19 +1106,18 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore
   namespace: default
 spec:
-  prefix: "/"
-  service: petstore.default:80
+  idle_timeout_ms: 43000
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
   timeout_ms: 42000
-  idle_timeout_ms: 43000
 `,
 		},
 		{
@@ -1145,14 +1129,14 @@ spec:
 					Name:      "petstore",
 				},
 				Timeouts: options.TimeoutOptions{
-					RequestTimeout: 42,
-					IdleTimeout:    43,
+					RequestTimeout: options.Duration(42 * time.Second),
+					IdleTimeout:    options.Duration(43 * time.Second),
 				},
 				PathSubOptions: map[string]options.SubOptions{
 					"/pet": {
 						Timeouts: options.TimeoutOptions{
-							RequestTimeout: 35,
-							IdleTimeout:    36,
+							RequestTimeout: options.Duration(35 * time.Second),
+							IdleTimeout:    options.Duration(36 * time.Second),
 						},
 					},
 				},
@@ -1183,20 +1167,19 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
+  idle_timeout_ms: 36000
   method: PUT
-  service: petstore.default:80
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:80
   timeout_ms: 35000
-  idle_timeout_ms: 36000
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -1204,13 +1187,13 @@ metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
+  idle_timeout_ms: 43000
   method: POST
-  service: petstore.default:80
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
   timeout_ms: 42000
-  idle_timeout_ms: 43000
 `,
 		},
 		{
@@ -1241,18 +1224,17 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore
   namespace: default
 spec:
-  prefix: "/"
   host: somehost.io
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -1294,23 +1276,22 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore
   namespace: default
 spec:
-  prefix: "/"
-  service: petstore.default:80
-  rewrite: ""
   labels:
     ambassador:
-	  - group:
-		  - kusk-group-default
-      - request:
-          - remote-address
+    - group:
+      - kusk-group-default
+    - request:
+      - remote-address
+  prefix: /
+  rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: RateLimit
@@ -1319,12 +1300,12 @@ metadata:
 spec:
   domain: ambassador
   limits:
-    - pattern:
-      - "generic_key": "kusk-group-default"
-        "remote-address": "*"
-      rate: 100
-      burstFactor: 2
-      unit: second
+  - burstFactor: 2
+    pattern:
+    - generic_key: kusk-group-default
+      remote-address: '*'
+    rate: 100
+    unit: second
 `,
 		},
 		{
@@ -1374,24 +1355,23 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
-  method: PUT
-  service: petstore.default:80
-  rewrite: ""
   labels:
     ambassador:
-      - operation:
-          - kusk-operation-petstore-updatepet
-      - request:
-          - remote-address
+    - operation:
+      - kusk-operation-petstore-updatepet
+    - request:
+      - remote-address
+  method: PUT
+  prefix: /pet
+  rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -1399,17 +1379,17 @@ metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
-  method: POST
-  service: petstore.default:80
-  rewrite: ""
   labels:
     ambassador:
-      - operation:
-          - kusk-operation-petstore-uploadfile
-      - request:
-          - remote-address
+    - operation:
+      - kusk-operation-petstore-uploadfile
+    - request:
+      - remote-address
+  method: POST
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
+  rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: RateLimit
@@ -1418,12 +1398,12 @@ metadata:
 spec:
   domain: ambassador
   limits:
-    - pattern:
-      - "generic_key": "kusk-operation-petstore-updatepet"
-        "remote-address": "*"
-      rate: 20
-      burstFactor: 2
-      unit: second
+  - burstFactor: 2
+    pattern:
+    - generic_key: kusk-operation-petstore-updatepet
+      remote-address: '*'
+    rate: 20
+    unit: second
 ---
 apiVersion: getambassador.io/v2
 kind: RateLimit
@@ -1432,12 +1412,12 @@ metadata:
 spec:
   domain: ambassador
   limits:
-    - pattern:
-      - "generic_key": "kusk-operation-petstore-uploadfile"
-        "remote-address": "*"
-      rate: 40
-      burstFactor: 2
-      unit: second
+  - burstFactor: 2
+    pattern:
+    - generic_key: kusk-operation-petstore-uploadfile
+      remote-address: '*'
+    rate: 40
+    unit: second
 `,
 		},
 		{
@@ -1489,24 +1469,23 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
-  method: PUT
-  service: petstore.default:80
-  rewrite: ""
   labels:
     ambassador:
-	  - group:
-		  - kusk-group-xyz
-      - request:
-          - remote-address
+    - group:
+      - kusk-group-xyz
+    - request:
+      - remote-address
+  method: PUT
+  prefix: /pet
+  rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -1514,17 +1493,17 @@ metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
-  method: POST
-  service: petstore.default:80
-  rewrite: ""
   labels:
     ambassador:
-	  - group:
-		  - kusk-group-xyz
-      - request:
-          - remote-address
+    - group:
+      - kusk-group-xyz
+    - request:
+      - remote-address
+  method: POST
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
+  rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: RateLimit
@@ -1533,12 +1512,12 @@ metadata:
 spec:
   domain: ambassador
   limits:
-    - pattern:
-      - "generic_key": "kusk-group-xyz"
-        "remote-address": "*"
-      rate: 20
-      burstFactor: 2
-      unit: second
+  - burstFactor: 2
+    pattern:
+    - generic_key: kusk-group-xyz
+      remote-address: '*'
+    rate: 20
+    unit: second
 `,
 		},
 		{
@@ -1563,8 +1542,7 @@ paths:
     get: {}
     post: {}
 `,
-			res: `
-`,
+			res: ``,
 		},
 		{
 			name: "path disabled, operation enabled",
@@ -1599,18 +1577,17 @@ paths:
         disabled: false
     post: {}
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-get
   namespace: default
 spec:
-  prefix: "/"
   method: GET
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -1650,18 +1627,17 @@ paths:
       x-kusk:
         disabled: false
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-patch
   namespace: default
 spec:
-  prefix: "/"
   method: PATCH
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -1669,10 +1645,10 @@ metadata:
   name: petstore-post
   namespace: default
 spec:
-  prefix: "/"
   method: POST
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -1702,18 +1678,17 @@ paths:
     post: {}
     patch: {}
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: petstore-patch
   namespace: default
 spec:
-  prefix: "/"
   method: PATCH
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -1721,10 +1696,10 @@ metadata:
   name: petstore-post
   namespace: default
 spec:
-  prefix: "/"
   method: POST
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -1764,18 +1739,17 @@ paths:
 					Port:      7000,
 				},
 			},
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: webapp
   namespace: booksapp
 spec:
-  prefix: "/my-bookstore"
-  host: *
+  host: '*'
+  prefix: /my-bookstore
+  rewrite: /bookstore/
   service: webapp.booksapp:7000
-  rewrite: "/bookstore/"
 `,
 		},
 		{
@@ -1832,19 +1806,18 @@ paths:
 					},
 				},
 			},
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v2
 kind: Mapping
 metadata:
   name: webapp-get
   namespace: booksapp
 spec:
-  prefix: "/my-bookstore/"
-  host: *
+  host: '*'
   method: GET
+  prefix: /my-bookstore/
+  rewrite: /bookstore/
   service: webapp.booksapp:7000
-  rewrite: "/bookstore/"
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -1852,12 +1825,12 @@ metadata:
   name: webapp-getbooksid
   namespace: booksapp
 spec:
-  prefix: "/my-bookstore/books/([a-zA-Z0-9]*)"
-  prefix_regex: true
-  host: *
+  host: '*'
   method: GET
+  prefix: /my-bookstore/books/([0-9]+)
+  prefix_regex: true
+  rewrite: /bookstore/books/([0-9]+)
   service: webapp.booksapp:7000
-  rewrite: "/bookstore/books/([a-zA-Z0-9]*)"
 ---
 apiVersion: getambassador.io/v2
 kind: Mapping
@@ -1865,11 +1838,11 @@ metadata:
   name: webapp-postbooks
   namespace: booksapp
 spec:
-  prefix: "/my-bookstore/books"
-  host: *
+  host: '*'
   method: POST
+  prefix: /my-bookstore/books
+  rewrite: /bookstore/books
   service: webapp.booksapp:7000
-  rewrite: "/bookstore/books"
 `,
 		},
 	}
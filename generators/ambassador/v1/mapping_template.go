@@ -15,7 +15,7 @@ spec:
   {{end}}
 
   {{ if .Host}}
-  host: {{.Host}}
+  host: '{{.Host}}'
   {{end}}
 
   {{if .Method}}
@@ -47,8 +47,8 @@ spec:
   {{if .LabelsEnabled}}
   labels:
     ambassador:{{if .RateLimitGroup}}
-	  - group:
-		  - kusk-group-{{.RateLimitGroup}}{{else}}
+      - group:
+          - kusk-group-{{.RateLimitGroup}}{{else}}
       - operation:
           - kusk-operation-{{.MappingName}}{{end}}
       - request:
@@ -62,10 +62,21 @@ func (g *Generator) Flags() *pflag.FlagSet {
 }
 
 func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	res, err := generators.GenerateObjects(g, opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	res.LogWarnings()
+
+	return res.YAML()
+}
+
+func (g *Generator) GenerateObjects(opts *options.Options, spec *openapi3.T) (*generators.Result, error) {
 	if opts.Host == "" {
-		return "", errors.New("host option is required for ambassador 2.0")
+		return nil, errors.New("host option is required for ambassador 2.0")
 	}
-	return g.abstractGenerator.Generate(opts, spec)
+	return g.abstractGenerator.GenerateObjects(opts, spec)
 }
 
 // template func for splitting comma separated strings into an array for iteration
@@ -3,6 +3,7 @@ package v2
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/require"
@@ -57,19 +58,18 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   hostname: '*'
   method: PUT
-  service: petstore.default:80
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -108,19 +108,18 @@ spec:
   }
 }
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   hostname: '*'
   method: PUT
-  service: petstore.default:80
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -151,19 +150,18 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: amb
 spec:
-  prefix: "/pet"
   hostname: '*'
   method: PUT
-  service: petstore.default:80
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -202,20 +200,19 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   hostname: '*'
   method: POST
-  service: petstore.default:80
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -253,20 +250,19 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-postpetpetiduploadimage
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   hostname: '*'
   method: POST
-  service: petstore.default:80
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -304,20 +300,19 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-postpetpetiduploadimage
   namespace: default
 spec:
-  prefix: "/api/v3/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   hostname: '*'
   method: POST
-  service: petstore.default:80
+  prefix: /api/v3/pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -361,18 +356,17 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore
   namespace: default
 spec:
-  prefix: "/api/v3"
   hostname: '*'
-  service: petstore.default:80
+  prefix: /api/v3
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -410,22 +404,21 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-postpetpetiduploadimage
   namespace: default
 spec:
-  prefix: "/petstore/api/v3/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   hostname: '*'
   method: POST
-  service: petstore.default:80
+  prefix: /petstore/api/v3/pet/([0-9]+)/uploadImage
+  prefix_regex: true
   regex_rewrite:
-    pattern: '/petstore(.*)'
-    substitution: '\1'
+    pattern: /petstore(.*)
+    substitution: \1
+  service: petstore.default:80
 `,
 		},
 		{
@@ -526,19 +519,18 @@ definitions:
       message:
         type: string
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-createpets
   namespace: default
 spec:
-  prefix: "/pets"
   hostname: '*'
   method: POST
-  service: petstore.default:80
+  prefix: /pets
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -546,11 +538,11 @@ metadata:
   name: petstore-listpets
   namespace: default
 spec:
-  prefix: "/pets"
   hostname: '*'
   method: GET
-  service: petstore.default:80
+  prefix: /pets
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -558,12 +550,12 @@ metadata:
   name: petstore-showpetbyid
   namespace: default
 spec:
-  prefix: "/pets/([a-zA-Z0-9]*)"
-  prefix_regex: true
   hostname: '*'
   method: GET
-  service: petstore.default:80
+  prefix: /pets/([^/]+)
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -707,19 +699,18 @@ spec:
   }
 }
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-createpets
   namespace: default
 spec:
-  prefix: "/pets"
   hostname: '*'
   method: POST
-  service: petstore.default:80
+  prefix: /pets
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -727,11 +718,11 @@ metadata:
   name: petstore-listpets
   namespace: default
 spec:
-  prefix: "/pets"
   hostname: '*'
   method: GET
-  service: petstore.default:80
+  prefix: /pets
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -739,12 +730,12 @@ metadata:
   name: petstore-showpetbyid
   namespace: default
 spec:
-  prefix: "/pets/([a-zA-Z0-9]*)"
-  prefix_regex: true
   hostname: '*'
   method: GET
-  service: petstore.default:80
+  prefix: /pets/([^/]+)
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -776,19 +767,18 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   hostname: '*'
   method: PUT
-  service: petstore.default:443
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:443
 `,
 		},
 		{
@@ -820,19 +810,18 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   hostname: '*'
   method: PUT
-  service: petstore.default:80
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -878,20 +867,19 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   hostname: '*'
   method: POST
-  service: petstore.default:80
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -937,20 +925,19 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   hostname: '*'
   method: POST
-  service: petstore.default:80
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -997,33 +984,32 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore
   namespace: default
 spec:
-  prefix: "/"
-  hostname: '*'
-  service: petstore.default:80
-  rewrite: ""
   cors:
-    origins:
-      - "http://foo.example"
-      - "http://bar.example"
-    methods:
-      - "POST"
-      - "GET"
-      - "OPTIONS"
-    headers:
-      - "Content-Type"
-    exposed_headers:
-      - "X-Custom-Header"
-      - "X-Other-Custom-Header"
     credentials: false
+    exposed_headers:
+    - X-Custom-Header
+    - X-Other-Custom-Header
+    headers:
+    - Content-Type
     max_age: "120"
+    methods:
+    - POST
+    - GET
+    - OPTIONS
+    origins:
+    - http://foo.example
+    - http://bar.example
+  hostname: '*'
+  prefix: /
+  rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -1044,7 +1030,7 @@ spec:
 					MaxAge:        120,
 				},
 				Timeouts: options.TimeoutOptions{
-					RequestTimeout: 5,
+					RequestTimeout: options.Duration(5 * time.Second),
 				},
 				PathSubOptions: map[string]options.SubOptions{
 					"/pet": {
@@ -1085,31 +1071,30 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
-  hostname: '*'
-  method: PUT
-  service: petstore.default:80
-  rewrite: ""
   cors:
-    origins:
-      - "http://bar.example"
-    methods:
-      - "POST"
-    headers:
-      - "Content-Type"
-    exposed_headers:
-      - "X-Custom-Header"
-      - "X-Other-Custom-Header"
     credentials: false
+    exposed_headers:
+    - X-Custom-Header
+    - X-Other-Custom-Header
+    headers:
+    - Content-Type
     max_age: "240"
+    methods:
+    - POST
+    origins:
+    - http://bar.example
+  hostname: '*'
+  method: PUT
+  prefix: /pet
+  rewrite: ""
+  service: petstore.default:80
   timeout_ms: 5000
 ---
 apiVersion: getambassador.io/v3alpha1
@@ -1118,27 +1103,27 @@ metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
-  hostname: '*'
-  method: POST
-  service: petstore.default:80
-  rewrite: ""
   cors:
-    origins:
-      - "http://foo.example"
-      - "http://bar.example"
-    methods:
-      - "POST"
-      - "GET"
-      - "OPTIONS"
-    headers:
-      - "Content-Type"
-    exposed_headers:
-      - "X-Custom-Header"
-      - "X-Other-Custom-Header"
     credentials: false
+    exposed_headers:
+    - X-Custom-Header
+    - X-Other-Custom-Header
+    headers:
+    - Content-Type
     max_age: "120"
+    methods:
+    - POST
+    - GET
+    - OPTIONS
+    origins:
+    - http://foo.example
+    - http://bar.example
+  hostname: '*'
+  method: POST
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
+  rewrite: ""
+  service: petstore.default:80
   timeout_ms: 5000
 `,
 		},
@@ -1152,8 +1137,8 @@ spec:
 					Name:      "petstore",
 				},
 				Timeouts: options.TimeoutOptions{
-					RequestTimeout: 42,
-					IdleTimeout:    43,
+					RequestTimeout: options.Duration(42 * time.Second),
+					IdleTimeout:    options.Duration(43 * time.Second),
 				},
 			},
 			spec: `
@@ -1182,20 +1167,19 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore
   namespace: default
 spec:
-  prefix: "/"
   hostname: '*'
-  service: petstore.default:80
+  idle_timeout_ms: 43000
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
   timeout_ms: 42000
-  idle_timeout_ms: 43000
 `,
 		},
 		{
@@ -1208,14 +1192,14 @@ spec:
 					Name:      "petstore",
 				},
 				Timeouts: options.TimeoutOptions{
-					RequestTimeout: 42,
-					IdleTimeout:    43,
+					RequestTimeout: options.Duration(42 * time.Second),
+					IdleTimeout:    options.Duration(43 * time.Second),
 				},
 				PathSubOptions: map[string]options.SubOptions{
 					"/pet": {
 						Timeouts: options.TimeoutOptions{
-							RequestTimeout: 35,
-							IdleTimeout:    36,
+							RequestTimeout: options.Duration(35 * time.Second),
+							IdleTimeout:    options.Duration(36 * time.Second),
 						},
 					},
 				},
@@ -1246,21 +1230,20 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   hostname: '*'
+  idle_timeout_ms: 36000
   method: PUT
-  service: petstore.default:80
+  prefix: /pet
   rewrite: ""
+  service: petstore.default:80
   timeout_ms: 35000
-  idle_timeout_ms: 36000
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -1268,14 +1251,14 @@ metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   hostname: '*'
+  idle_timeout_ms: 43000
   method: POST
-  service: petstore.default:80
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
   rewrite: ""
+  service: petstore.default:80
   timeout_ms: 42000
-  idle_timeout_ms: 43000
 `,
 		},
 		{
@@ -1306,18 +1289,17 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore
   namespace: default
 spec:
-  prefix: "/"
-  hostname: 'somehost.io'
-  service: petstore.default:80
+  hostname: somehost.io
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -1360,24 +1342,23 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore
   namespace: default
 spec:
-  prefix: "/"
   hostname: '*'
-  service: petstore.default:80
-  rewrite: ""
   labels:
     ambassador:
-	  - group:
-		  - kusk-group-default
-      - request:
-          - remote-address
+    - group:
+      - kusk-group-default
+    - request:
+      - remote-address
+  prefix: /
+  rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: RateLimit
@@ -1386,12 +1367,12 @@ metadata:
 spec:
   domain: ambassador
   limits:
-    - pattern:
-      - "generic_key": "kusk-group-default"
-        "remote-address": "*"
-      rate: 100
-      burstFactor: 2
-      unit: second
+  - burstFactor: 2
+    pattern:
+    - generic_key: kusk-group-default
+      remote-address: '*'
+    rate: 100
+    unit: second
 `,
 		},
 		{
@@ -1442,25 +1423,24 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   hostname: '*'
-  method: PUT
-  service: petstore.default:80
-  rewrite: ""
   labels:
     ambassador:
-      - operation:
-          - kusk-operation-petstore-updatepet
-      - request:
-          - remote-address
+    - operation:
+      - kusk-operation-petstore-updatepet
+    - request:
+      - remote-address
+  method: PUT
+  prefix: /pet
+  rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -1468,18 +1448,18 @@ metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   hostname: '*'
-  method: POST
-  service: petstore.default:80
-  rewrite: ""
   labels:
     ambassador:
-      - operation:
-          - kusk-operation-petstore-uploadfile
-      - request:
-          - remote-address
+    - operation:
+      - kusk-operation-petstore-uploadfile
+    - request:
+      - remote-address
+  method: POST
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
+  rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: RateLimit
@@ -1488,12 +1468,12 @@ metadata:
 spec:
   domain: ambassador
   limits:
-    - pattern:
-      - "generic_key": "kusk-operation-petstore-updatepet"
-        "remote-address": "*"
-      rate: 20
-      burstFactor: 2
-      unit: second
+  - burstFactor: 2
+    pattern:
+    - generic_key: kusk-operation-petstore-updatepet
+      remote-address: '*'
+    rate: 20
+    unit: second
 ---
 apiVersion: getambassador.io/v2
 kind: RateLimit
@@ -1502,12 +1482,12 @@ metadata:
 spec:
   domain: ambassador
   limits:
-    - pattern:
-      - "generic_key": "kusk-operation-petstore-uploadfile"
-        "remote-address": "*"
-      rate: 40
-      burstFactor: 2
-      unit: second
+  - burstFactor: 2
+    pattern:
+    - generic_key: kusk-operation-petstore-uploadfile
+      remote-address: '*'
+    rate: 40
+    unit: second
 `,
 		},
 		{
@@ -1560,25 +1540,24 @@ paths:
       responses:
         '200':
           description: Successful operation`,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-updatepet
   namespace: default
 spec:
-  prefix: "/pet"
   hostname: '*'
-  method: PUT
-  service: petstore.default:80
-  rewrite: ""
   labels:
     ambassador:
-	  - group:
-		  - kusk-group-xyz
-      - request:
-          - remote-address
+    - group:
+      - kusk-group-xyz
+    - request:
+      - remote-address
+  method: PUT
+  prefix: /pet
+  rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -1586,18 +1565,18 @@ metadata:
   name: petstore-uploadfile
   namespace: default
 spec:
-  prefix: "/pet/([a-zA-Z0-9]*)/uploadImage"
-  prefix_regex: true
   hostname: '*'
-  method: POST
-  service: petstore.default:80
-  rewrite: ""
   labels:
     ambassador:
-	  - group:
-		  - kusk-group-xyz
-      - request:
-          - remote-address
+    - group:
+      - kusk-group-xyz
+    - request:
+      - remote-address
+  method: POST
+  prefix: /pet/([0-9]+)/uploadImage
+  prefix_regex: true
+  rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v2
 kind: RateLimit
@@ -1606,12 +1585,12 @@ metadata:
 spec:
   domain: ambassador
   limits:
-    - pattern:
-      - "generic_key": "kusk-group-xyz"
-        "remote-address": "*"
-      rate: 20
-      burstFactor: 2
-      unit: second
+  - burstFactor: 2
+    pattern:
+    - generic_key: kusk-group-xyz
+      remote-address: '*'
+    rate: 20
+    unit: second
 `,
 		},
 		{
@@ -1637,8 +1616,7 @@ paths:
     get: {}
     post: {}
 `,
-			res: `
-`,
+			res: ``,
 		},
 		{
 			name: "path disabled, operation enabled",
@@ -1674,19 +1652,18 @@ paths:
         disabled: false
     post: {}
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-get
   namespace: default
 spec:
-  prefix: "/"
   hostname: '*'
   method: GET
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -1727,19 +1704,18 @@ paths:
       x-kusk:
         disabled: false
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-patch
   namespace: default
 spec:
-  prefix: "/"
   hostname: '*'
   method: PATCH
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -1747,11 +1723,11 @@ metadata:
   name: petstore-post
   namespace: default
 spec:
-  prefix: "/"
   hostname: '*'
   method: POST
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -1782,19 +1758,18 @@ paths:
     post: {}
     patch: {}
 `,
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: petstore-patch
   namespace: default
 spec:
-  prefix: "/"
   hostname: '*'
   method: PATCH
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -1802,11 +1777,11 @@ metadata:
   name: petstore-post
   namespace: default
 spec:
-  prefix: "/"
   hostname: '*'
   method: POST
-  service: petstore.default:80
+  prefix: /
   rewrite: ""
+  service: petstore.default:80
 `,
 		},
 		{
@@ -1846,18 +1821,17 @@ paths:
 					Port:      7000,
 				},
 			},
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: webapp
   namespace: booksapp
 spec:
-  prefix: "/my-bookstore"
   hostname: '*'
+  prefix: /my-bookstore
+  rewrite: /bookstore/
   service: webapp.booksapp:7000
-  rewrite: "/bookstore/"
 `,
 		},
 		{
@@ -1914,19 +1888,18 @@ paths:
 					},
 				},
 			},
-			res: `
----
+			res: `---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
 metadata:
   name: webapp-get
   namespace: booksapp
 spec:
-  prefix: "/my-bookstore/"
   hostname: '*'
   method: GET
+  prefix: /my-bookstore/
+  rewrite: /bookstore/
   service: webapp.booksapp:7000
-  rewrite: "/bookstore/"
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -1934,12 +1907,12 @@ metadata:
   name: webapp-getbooksid
   namespace: booksapp
 spec:
-  prefix: "/my-bookstore/books/([a-zA-Z0-9]*)"
-  prefix_regex: true
   hostname: '*'
   method: GET
+  prefix: /my-bookstore/books/([0-9]+)
+  prefix_regex: true
+  rewrite: /bookstore/books/([0-9]+)
   service: webapp.booksapp:7000
-  rewrite: "/bookstore/books/([a-zA-Z0-9]*)"
 ---
 apiVersion: getambassador.io/v3alpha1
 kind: Mapping
@@ -1947,11 +1920,11 @@ metadata:
   name: webapp-postbooks
   namespace: booksapp
 spec:
-  prefix: "/my-bookstore/books"
   hostname: '*'
   method: POST
+  prefix: /my-bookstore/books
+  rewrite: /bookstore/books
   service: webapp.booksapp:7000
-  rewrite: "/bookstore/books"
 `,
 		},
 	}
@@ -1976,3 +1949,123 @@ spec:
 		})
 	}
 }
+
+func TestAmbassadorPathHostOverride(t *testing.T) {
+	r := require.New(t)
+
+	opts := options.Options{
+		Namespace: "default",
+		Host:      "books.example.org",
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "webapp",
+		},
+		Path: options.PathOptions{
+			Split: true,
+		},
+		PathSubOptions: map[string]options.SubOptions{
+			"/admin": {Host: "admin.example.org"},
+		},
+		OperationSubOptions: map[string]options.SubOptions{
+			"POST/books": {Host: "api.example.org"},
+		},
+	}
+
+	spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+paths:
+  "/admin":
+    get:
+      operationId: getAdmin
+  "/authors":
+    get:
+      operationId: listAuthors
+  "/books":
+    get:
+      operationId: listBooks
+    post:
+      operationId: createBook
+  "/publishers":
+    get:
+      operationId: listPublishers
+`))
+	r.NoError(err, "failed to parse spec")
+
+	gen := Generator{
+		abstractGenerator: ambassador.AbstractGenerator{
+			MappingTemplate:   mappingTemplate,
+			RateLimitTemplate: rateLimitTemplate,
+		},
+	}
+
+	// paths are visited in a random order, generate several times so that the overridden host
+	// would leak into the mappings of the paths visited after /admin and POST /books
+	for i := 0; i < 10; i++ {
+		mappings, err := gen.Generate(&opts, spec)
+		r.NoError(err)
+		r.Equal(`---
+apiVersion: getambassador.io/v3alpha1
+kind: Mapping
+metadata:
+  name: webapp-createbook
+  namespace: default
+spec:
+  hostname: api.example.org
+  method: POST
+  prefix: /books
+  rewrite: ""
+  service: webapp.default:80
+---
+apiVersion: getambassador.io/v3alpha1
+kind: Mapping
+metadata:
+  name: webapp-getadmin
+  namespace: default
+spec:
+  hostname: admin.example.org
+  method: GET
+  prefix: /admin
+  rewrite: ""
+  service: webapp.default:80
+---
+apiVersion: getambassador.io/v3alpha1
+kind: Mapping
+metadata:
+  name: webapp-listauthors
+  namespace: default
+spec:
+  hostname: books.example.org
+  method: GET
+  prefix: /authors
+  rewrite: ""
+  service: webapp.default:80
+---
+apiVersion: getambassador.io/v3alpha1
+kind: Mapping
+metadata:
+  name: webapp-listbooks
+  namespace: default
+spec:
+  hostname: books.example.org
+  method: GET
+  prefix: /books
+  rewrite: ""
+  service: webapp.default:80
+---
+apiVersion: getambassador.io/v3alpha1
+kind: Mapping
+metadata:
+  name: webapp-listpublishers
+  namespace: default
+spec:
+  hostname: books.example.org
+  method: GET
+  prefix: /publishers
+  rewrite: ""
+  service: webapp.default:80
+`, mappings)
+	}
+}
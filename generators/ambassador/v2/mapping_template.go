@@ -75,8 +75,8 @@ spec:
   {{if .LabelsEnabled}}
   labels:
     ambassador:{{if .RateLimitGroup}}
-	  - group:
-		  - kusk-group-{{.RateLimitGroup}}{{else}}
+      - group:
+          - kusk-group-{{.RateLimitGroup}}{{else}}
       - operation:
           - kusk-operation-{{.MappingName}}{{end}}
       - request:
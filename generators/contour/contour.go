@@ -0,0 +1,377 @@
+package contour
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/generators/pathparams"
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+const (
+	apiVersion    = "projectcontour.io/v1"
+	httpProxyKind = "HTTPProxy"
+)
+
+var (
+	openApiPathVariableRegex = regexp.MustCompile(`{[^}]+}`)
+)
+
+func init() {
+	generators.Registry["contour"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "contour"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("contour", pflag.ExitOnError)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"path.trim_prefix",
+		"",
+		"a prefix to trim from the URL before forwarding to the upstream Service",
+	)
+
+	fs.String(
+		"path.rewrite",
+		"",
+		"rewrite your base path before forwarding to the upstream service",
+	)
+
+	fs.Uint32(
+		"rate_limits.rps",
+		0,
+		"request per second rate limit",
+	)
+
+	fs.Uint32(
+		"rate_limits.burst",
+		0,
+		"request per second burst",
+	)
+
+	fs.Var(
+		new(options.Duration),
+		"timeouts.request_timeout",
+		"total request timeout, in seconds or as a duration, e.g. 1500ms",
+	)
+
+	fs.Var(
+		new(options.Duration),
+		"timeouts.idle_timeout",
+		"idle connection timeout, in seconds or as a duration, e.g. 2m",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"the fully qualified domain name of the virtual host",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates Contour HTTPProxy resources"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+// route is a Contour route generated for a single operation
+// along with the data needed to order and group it into an HTTPProxy
+type route struct {
+	host   string
+	path   string
+	method string
+
+	route Route
+	// rewriteSkipped tells that the path rewrite couldn't be applied to the route
+	rewriteSkipped bool
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	res, err := generators.GenerateObjects(g, opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	res.LogWarnings()
+
+	return res.YAML()
+}
+
+func (g *Generator) GenerateObjects(opts *options.Options, spec *openapi3.T) (*generators.Result, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return nil, fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	res := &generators.Result{}
+
+	corsWarned := false
+
+	var routes []route
+
+	for path, pathItem := range spec.Paths {
+		for method, operation := range pathItem.Operations() {
+			if opts.IsOperationDisabled(path, method) {
+				continue
+			}
+
+			// CORS policy is a property of the virtual host in Contour
+			if !corsWarned && !reflect.DeepEqual(opts.CORS, opts.GetCORSOpts(path, method)) {
+				res.Warn("Contour only supports CORS options on the virtual host level, path and operation level CORS options will be ignored")
+
+				corsWarned = true
+			}
+
+			r, rewriteSkipped := generateRoute(opts, path, method, pathparams.Find(pathItem, operation))
+
+			routes = append(routes, route{
+				host:           opts.GetHost(path, method),
+				path:           path,
+				method:         method,
+				route:          r,
+				rewriteSkipped: rewriteSkipped,
+			})
+		}
+	}
+
+	if len(routes) == 0 {
+		return res, nil
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return generators.OperationLess(routes[i].path, routes[i].method, routes[j].path, routes[j].method)
+	})
+
+	prefixWarned := false
+
+	for _, r := range routes {
+		if r.rewriteSkipped {
+			res.Warn(
+				"%s %s: path.trim_prefix and path.rewrite can't be used with path parameters, "+
+					"as Contour only rewrites the prefix of prefix conditions, the path is forwarded as is",
+				r.method, r.path,
+			)
+		}
+
+		if !prefixWarned && r.route.PathRewritePolicy != nil {
+			res.Warn(
+				"Contour only rewrites the prefix of prefix conditions, so the routes with a path rewrite "+
+					"also match the paths under them, e.g. %s/...",
+				r.route.Conditions[0].Prefix,
+			)
+
+			prefixWarned = true
+		}
+	}
+
+	// virtual host is set on the HTTPProxy level, so operations with a different host
+	// are grouped into their own HTTPProxy
+	var hosts []string
+	routesByHost := map[string][]Route{}
+
+	for _, r := range routes {
+		if _, ok := routesByHost[r.host]; !ok {
+			hosts = append(hosts, r.host)
+		}
+
+		routesByHost[r.host] = append(routesByHost[r.host], r.route)
+	}
+
+	proxies := make([]HTTPProxy, 0, len(hosts))
+
+	for _, host := range hosts {
+		name := generators.HostResourceName(opts, host)
+
+		proxy := HTTPProxy{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiVersion,
+				Kind:       httpProxyKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: opts.Namespace,
+			},
+			Spec: HTTPProxySpec{
+				Routes: routesByHost[host],
+			},
+		}
+
+		// HTTPProxy without a virtual host can only be included by another root HTTPProxy
+		if host != "" {
+			proxy.Spec.VirtualHost = &VirtualHost{
+				Fqdn: host,
+			}
+
+			if !reflect.DeepEqual(options.CORSOptions{}, opts.CORS) {
+				proxy.Spec.VirtualHost.CORSPolicy = generateCORSPolicy(&opts.CORS)
+			}
+		} else if !reflect.DeepEqual(options.CORSOptions{}, opts.CORS) {
+			res.Warn("Contour only supports CORS options on the virtual host level, HTTPProxy %s has no host so the CORS options will be ignored", name)
+		}
+
+		proxies = append(proxies, proxy)
+	}
+
+	sort.Slice(proxies, func(i, j int) bool {
+		return proxies[i].Name < proxies[j].Name
+	})
+
+	for _, proxy := range proxies {
+		if err := res.AddObject(proxy); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// generateRoute returns the route of the operation and whether the path rewrite was skipped,
+// as it can't be applied to the paths with parameters
+func generateRoute(opts *options.Options, path, method string, params map[string]*openapi3.Parameter) (Route, bool) {
+	fullPath := strings.TrimSuffix(opts.Path.Base, "/") + path
+	templated := openApiPathVariableRegex.MatchString(path)
+
+	pathCondition := MatchCondition{Exact: fullPath}
+	if templated {
+		pathCondition = MatchCondition{Regex: pathparams.Regex(fullPath, params, nil)}
+	}
+
+	var pathRewritePolicy *PathRewritePolicy
+
+	// Contour only replaces the prefix of a prefix condition, so literal paths are matched
+	// as prefixes, while the paths with parameters are forwarded without rewriting
+	rewritten := opts.Path.RewritePath(fullPath)
+	rewriteSkipped := rewritten != fullPath && templated
+
+	if rewritten != fullPath && !templated {
+		pathCondition = MatchCondition{Prefix: fullPath}
+		pathRewritePolicy = &PathRewritePolicy{
+			ReplacePrefix: []ReplacePrefix{
+				{
+					Replacement: rewritten,
+				},
+			},
+		}
+	}
+
+	res := Route{
+		Conditions: []MatchCondition{
+			pathCondition,
+			{
+				Header: &HeaderMatchCondition{
+					Name:  ":method",
+					Exact: method,
+				},
+			},
+		},
+		Services: []Service{
+			{
+				Name: opts.Service.Name,
+				Port: opts.Service.Port,
+			},
+		},
+		PathRewritePolicy: pathRewritePolicy,
+	}
+
+	if timeoutOpts := opts.GetTimeoutOpts(path, method); !reflect.DeepEqual(options.TimeoutOptions{}, timeoutOpts) {
+		res.TimeoutPolicy = &TimeoutPolicy{}
+
+		if timeoutOpts.RequestTimeout > 0 {
+			res.TimeoutPolicy.Response = timeoutOpts.RequestTimeout.String()
+		}
+
+		if timeoutOpts.IdleTimeout > 0 {
+			res.TimeoutPolicy.Idle = timeoutOpts.IdleTimeout.String()
+		}
+	}
+
+	if rateLimitOpts := opts.GetRateLimitOpts(path, method); rateLimitOpts.RPS > 0 {
+		local := &LocalRateLimitPolicy{
+			Requests: rateLimitOpts.RPS,
+			Unit:     "second",
+		}
+
+		// Contour burst is the number of requests allowed on top of the base rate
+		if rateLimitOpts.Burst > rateLimitOpts.RPS {
+			local.Burst = rateLimitOpts.Burst - rateLimitOpts.RPS
+		}
+
+		res.RateLimitPolicy = &RateLimitPolicy{Local: local}
+	}
+
+	return res, rewriteSkipped
+}
+
+func generateCORSPolicy(corsOpts *options.CORSOptions) *CORSPolicy {
+	res := &CORSPolicy{
+		AllowOrigin:   corsOpts.Origins,
+		AllowMethods:  corsOpts.Methods,
+		AllowHeaders:  corsOpts.Headers,
+		ExposeHeaders: corsOpts.ExposeHeaders,
+	}
+
+	if corsOpts.Credentials != nil {
+		res.AllowCredentials = *corsOpts.Credentials
+	}
+
+	if corsOpts.MaxAge > 0 {
+		res.MaxAge = fmt.Sprintf("%ds", corsOpts.MaxAge)
+	}
+
+	return res
+}
+
+// Merge merges the HTTPProxies generated for several specs with the same namespace and virtual host into one,
+// as Contour rejects root HTTPProxies sharing a fqdn. The CORS policy of the first one is kept.
+func (g *Generator) Merge(name string, res *generators.Result) error {
+	key := func(obj *unstructured.Unstructured) (string, bool) {
+		fqdn, _, _ := unstructured.NestedString(obj.Object, "spec", "virtualhost", "fqdn")
+
+		return obj.GetNamespace() + "/" + fqdn, obj.GetKind() == httpProxyKind && fqdn != ""
+	}
+
+	merge := func(into, obj *unstructured.Unstructured) error {
+		var merged, proxy HTTPProxy
+
+		if err := generators.FromUnstructured(into, &merged); err != nil {
+			return err
+		}
+
+		if err := generators.FromUnstructured(obj, &proxy); err != nil {
+			return err
+		}
+
+		if !reflect.DeepEqual(merged.Spec.VirtualHost.CORSPolicy, proxy.Spec.VirtualHost.CORSPolicy) {
+			res.Warn("HTTPProxy %s has a different CORS policy than %s for %s, the CORS policy of %s is used", proxy.Name, merged.Name, merged.Spec.VirtualHost.Fqdn, merged.Name)
+		}
+
+		merged.Spec.Routes = append(merged.Spec.Routes, proxy.Spec.Routes...)
+
+		return generators.UpdateObject(into, merged)
+	}
+
+	return res.MergeObjects(name, key, merge)
+}
@@ -0,0 +1,420 @@
+package contour
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/spec"
+)
+
+func TestContour(t *testing.T) {
+	var testCases = []struct {
+		name string
+		spec string
+		res  string
+	}{
+		{
+			name: "virtual host, trim prefix, timeouts, rate limits and CORS",
+			spec: `openapi: 3.0.1
+x-kusk:
+  namespace: booksapp
+  host: books.example.org
+  path:
+    base: /bookstore
+    trim_prefix: /bookstore
+  timeouts:
+    request_timeout: 10
+    idle_timeout: 60
+  rate_limits:
+    rps: 100
+    burst: 150
+  cors:
+    origins:
+    - http://foo.example
+    methods:
+    - GET
+    - POST
+    credentials: true
+    max_age: 86400
+  service:
+    name: webapp
+    namespace: booksapp
+    port: 7000
+paths:
+  /books:
+    get: {}
+    post: {}
+  /authors:
+    x-kusk:
+      timeouts:
+        request_timeout: 2
+    get: {}
+`,
+			res: `---
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  creationTimestamp: null
+  name: webapp
+  namespace: booksapp
+spec:
+  routes:
+  - conditions:
+    - prefix: /bookstore/authors
+    - header:
+        exact: GET
+        name: :method
+    pathRewritePolicy:
+      replacePrefix:
+      - replacement: /authors
+    rateLimitPolicy:
+      local:
+        burst: 50
+        requests: 100
+        unit: second
+    services:
+    - name: webapp
+      port: 7000
+    timeoutPolicy:
+      idle: 60s
+      response: 2s
+  - conditions:
+    - prefix: /bookstore/books
+    - header:
+        exact: GET
+        name: :method
+    pathRewritePolicy:
+      replacePrefix:
+      - replacement: /books
+    rateLimitPolicy:
+      local:
+        burst: 50
+        requests: 100
+        unit: second
+    services:
+    - name: webapp
+      port: 7000
+    timeoutPolicy:
+      idle: 60s
+      response: 10s
+  - conditions:
+    - prefix: /bookstore/books
+    - header:
+        exact: POST
+        name: :method
+    pathRewritePolicy:
+      replacePrefix:
+      - replacement: /books
+    rateLimitPolicy:
+      local:
+        burst: 50
+        requests: 100
+        unit: second
+    services:
+    - name: webapp
+      port: 7000
+    timeoutPolicy:
+      idle: 60s
+      response: 10s
+  virtualhost:
+    corsPolicy:
+      allowCredentials: true
+      allowMethods:
+      - GET
+      - POST
+      allowOrigin:
+      - http://foo.example
+      maxAge: 86400s
+    fqdn: books.example.org
+`,
+		},
+		{
+			name: "host override and disabled operation",
+			spec: `openapi: 3.0.1
+x-kusk:
+  service:
+    name: petstore
+    namespace: default
+paths:
+  /pet:
+    get: {}
+    delete:
+      x-kusk:
+        disabled: true
+  /pet/{petId}:
+    get:
+      parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+  /store:
+    x-kusk:
+      host: store.example.org
+    get: {}
+`,
+			res: `---
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: default
+spec:
+  routes:
+  - conditions:
+    - exact: /pet
+    - header:
+        exact: GET
+        name: :method
+    services:
+    - name: petstore
+      port: 80
+  - conditions:
+    - regex: /pet/[0-9]+
+    - header:
+        exact: GET
+        name: :method
+    services:
+    - name: petstore
+      port: 80
+---
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  creationTimestamp: null
+  name: petstore-store-example-org
+  namespace: default
+spec:
+  routes:
+  - conditions:
+    - exact: /store
+    - header:
+        exact: GET
+        name: :method
+    services:
+    - name: petstore
+      port: 80
+  virtualhost:
+    fqdn: store.example.org
+`,
+		},
+	}
+
+	var gen Generator
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			opts, err := spec.GetOptions(apiSpec)
+			r.NoError(err, "failed to get options")
+
+			res, err := gen.Generate(opts, apiSpec)
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+func TestContourRewriteWithPathParameters(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+x-kusk:
+  path:
+    base: /bookstore
+    rewrite: /api
+  service:
+    name: webapp
+    namespace: booksapp
+paths:
+  /books:
+    get: {}
+  /books/{id}:
+    get: {}
+`))
+	r.NoError(err, "failed to parse spec")
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err, "failed to get options")
+
+	var gen Generator
+
+	res, err := gen.GenerateObjects(opts, apiSpec)
+	r.NoError(err)
+	r.Equal([]string{
+		"Contour only rewrites the prefix of prefix conditions, so the routes with a path rewrite also match the paths under them, e.g. /bookstore/books/...",
+		"GET /books/{id}: path.trim_prefix and path.rewrite can't be used with path parameters, " +
+			"as Contour only rewrites the prefix of prefix conditions, the path is forwarded as is",
+	}, res.Warnings)
+
+	yaml, err := res.YAML()
+	r.NoError(err)
+	r.Equal(`---
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  creationTimestamp: null
+  name: webapp
+  namespace: default
+spec:
+  routes:
+  - conditions:
+    - prefix: /bookstore/books
+    - header:
+        exact: GET
+        name: :method
+    pathRewritePolicy:
+      replacePrefix:
+      - replacement: /api/books
+    services:
+    - name: webapp
+      port: 80
+  - conditions:
+    - regex: /bookstore/books/[^/]+
+    - header:
+        exact: GET
+        name: :method
+    services:
+    - name: webapp
+      port: 80
+`, yaml)
+}
+
+func TestContourCORSWithoutHost(t *testing.T) {
+	r := require.New(t)
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+x-kusk:
+  service:
+    name: webapp
+    namespace: booksapp
+  cors:
+    origins:
+    - https://books.example.org
+paths:
+  /books:
+    get: {}
+`))
+	r.NoError(err, "failed to parse spec")
+
+	opts, err := spec.GetOptions(apiSpec)
+	r.NoError(err, "failed to get options")
+
+	var gen Generator
+
+	res, err := gen.GenerateObjects(opts, apiSpec)
+	r.NoError(err)
+	r.Equal([]string{
+		"Contour only supports CORS options on the virtual host level, HTTPProxy webapp has no host so the CORS options will be ignored",
+	}, res.Warnings)
+
+	r.Len(res.Objects, 1)
+	_, found, err := unstructured.NestedMap(res.Objects[0].Object, "spec", "virtualhost")
+	r.NoError(err)
+	r.False(found)
+}
+
+func TestMerge(t *testing.T) {
+	r := require.New(t)
+
+	specs := []string{`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+x-kusk:
+  host: api.example.org
+  path:
+    base: /books
+  service:
+    name: books
+    namespace: default
+  cors:
+    origins:
+    - https://books.example.org
+paths:
+  "/":
+    get: {}
+`, `
+openapi: 3.0.2
+info:
+  title: Authors
+  version: 1.0.0
+x-kusk:
+  host: api.example.org
+  path:
+    base: /authors
+  service:
+    name: authors
+    namespace: default
+paths:
+  "/":
+    get: {}
+`}
+
+	var gen Generator
+	var results []*generators.Result
+
+	for _, s := range specs {
+		apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(s))
+		r.NoError(err, "failed to parse spec")
+
+		opts, err := spec.GetOptions(apiSpec)
+		r.NoError(err, "failed to get options")
+
+		res, err := gen.GenerateObjects(opts, apiSpec)
+		r.NoError(err)
+
+		results = append(results, res)
+	}
+
+	res, err := generators.Merge(&gen, "", results)
+	r.NoError(err)
+
+	r.Equal([]string{"HTTPProxy authors has a different CORS policy than books for api.example.org, the CORS policy of books is used"}, res.Warnings)
+
+	actual, err := res.YAML()
+	r.NoError(err)
+	r.Equal(`---
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  creationTimestamp: null
+  name: books
+  namespace: default
+spec:
+  routes:
+  - conditions:
+    - exact: /books/
+    - header:
+        exact: GET
+        name: :method
+    services:
+    - name: books
+      port: 80
+  - conditions:
+    - exact: /authors/
+    - header:
+        exact: GET
+        name: :method
+    services:
+    - name: authors
+      port: 80
+  virtualhost:
+    corsPolicy:
+      allowMethods: null
+      allowOrigin:
+      - https://books.example.org
+    fqdn: api.example.org
+`, actual)
+}
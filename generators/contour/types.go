@@ -0,0 +1,83 @@
+package contour
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The types below mirror the subset of the projectcontour.io/v1 HTTPProxy API
+// that the generator produces, so that we don't have to depend on the Contour libraries.
+
+type HTTPProxy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HTTPProxySpec `json:"spec"`
+}
+
+type HTTPProxySpec struct {
+	VirtualHost *VirtualHost `json:"virtualhost,omitempty"`
+	Routes      []Route      `json:"routes,omitempty"`
+}
+
+type VirtualHost struct {
+	Fqdn       string      `json:"fqdn"`
+	CORSPolicy *CORSPolicy `json:"corsPolicy,omitempty"`
+}
+
+type CORSPolicy struct {
+	AllowCredentials bool     `json:"allowCredentials,omitempty"`
+	AllowOrigin      []string `json:"allowOrigin"`
+	AllowMethods     []string `json:"allowMethods"`
+	AllowHeaders     []string `json:"allowHeaders,omitempty"`
+	ExposeHeaders    []string `json:"exposeHeaders,omitempty"`
+	MaxAge           string   `json:"maxAge,omitempty"`
+}
+
+type Route struct {
+	Conditions        []MatchCondition   `json:"conditions,omitempty"`
+	Services          []Service          `json:"services"`
+	TimeoutPolicy     *TimeoutPolicy     `json:"timeoutPolicy,omitempty"`
+	RateLimitPolicy   *RateLimitPolicy   `json:"rateLimitPolicy,omitempty"`
+	PathRewritePolicy *PathRewritePolicy `json:"pathRewritePolicy,omitempty"`
+}
+
+type MatchCondition struct {
+	Prefix string                `json:"prefix,omitempty"`
+	Exact  string                `json:"exact,omitempty"`
+	Regex  string                `json:"regex,omitempty"`
+	Header *HeaderMatchCondition `json:"header,omitempty"`
+}
+
+type HeaderMatchCondition struct {
+	Name  string `json:"name"`
+	Exact string `json:"exact,omitempty"`
+}
+
+type Service struct {
+	Name string `json:"name"`
+	Port int32  `json:"port"`
+}
+
+type TimeoutPolicy struct {
+	Response string `json:"response,omitempty"`
+	Idle     string `json:"idle,omitempty"`
+}
+
+type RateLimitPolicy struct {
+	Local *LocalRateLimitPolicy `json:"local,omitempty"`
+}
+
+type LocalRateLimitPolicy struct {
+	Requests uint32 `json:"requests"`
+	Unit     string `json:"unit"`
+	Burst    uint32 `json:"burst,omitempty"`
+}
+
+type PathRewritePolicy struct {
+	ReplacePrefix []ReplacePrefix `json:"replacePrefix,omitempty"`
+}
+
+type ReplacePrefix struct {
+	Prefix      string `json:"prefix,omitempty"`
+	Replacement string `json:"replacement"`
+}
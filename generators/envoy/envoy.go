@@ -0,0 +1,453 @@
+package envoy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/generators/pathparams"
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+const (
+	corsFilterName      = "envoy.filters.http.cors"
+	rateLimitFilterName = "envoy.filters.http.local_ratelimit"
+
+	corsPolicyType     = "type.googleapis.com/envoy.extensions.filters.http.cors.v3.CorsPolicy"
+	localRateLimitType = "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"
+)
+
+func init() {
+	generators.Registry["envoy"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "envoy"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("envoy", pflag.ExitOnError)
+
+	fs.String(
+		"envoy.cluster",
+		"",
+		"the Envoy cluster to route traffic to (default is the service name)",
+	)
+
+	fs.String(
+		"envoy.format",
+		"yaml",
+		"output format of the route configuration, yaml or json",
+	)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"path.trim_prefix",
+		"",
+		"a prefix to trim from the URL before forwarding to the upstream Service",
+	)
+
+	fs.String(
+		"path.rewrite",
+		"",
+		"rewrite your base path before forwarding to the upstream service",
+	)
+
+	fs.Uint32(
+		"rate_limits.rps",
+		0,
+		"request per second rate limit",
+	)
+
+	fs.Uint32(
+		"rate_limits.burst",
+		0,
+		"request per second burst",
+	)
+
+	fs.Var(
+		new(options.Duration),
+		"timeouts.request_timeout",
+		"total request timeout, in seconds or as a duration, e.g. 1500ms",
+	)
+
+	fs.Var(
+		new(options.Duration),
+		"timeouts.idle_timeout",
+		"idle connection timeout, in seconds or as a duration, e.g. 2m",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"the Host header value to listen on",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates a standalone Envoy RouteConfiguration for your service"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+// route is an Envoy route generated for a single operation
+// along with the data needed to order and group it into a virtual host
+type route struct {
+	host  string
+	path  string
+	regex bool
+
+	method string
+	route  Route
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	res, err := generators.GenerateObjects(g, opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	res.LogWarnings()
+
+	return g.Marshal(opts, res)
+}
+
+// GenerateObjects returns the route configuration as a single object,
+// it has no apiVersion and kind as it is not a Kubernetes resource
+func (g *Generator) GenerateObjects(opts *options.Options, spec *openapi3.T) (*generators.Result, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return nil, fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	res := &generators.Result{}
+
+	cluster := opts.Envoy.Cluster
+	if cluster == "" {
+		cluster = opts.Service.Name
+	}
+
+	var routes []route
+
+	for path, pathItem := range spec.Paths {
+		for method, operation := range pathItem.Operations() {
+			if opts.IsOperationDisabled(path, method) {
+				continue
+			}
+
+			params := pathparams.Find(pathItem, operation)
+
+			routes = append(routes, route{
+				host:   opts.GetHost(path, method),
+				path:   path,
+				regex:  len(params) > 0,
+				method: method,
+				route:  generateRoute(opts, cluster, path, method, params, operation),
+			})
+		}
+	}
+
+	if len(routes) == 0 {
+		return res, nil
+	}
+
+	// Envoy evaluates routes in order and the first match wins,
+	// so literal paths go first to avoid being shadowed by regex matches
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].regex != routes[j].regex {
+			return !routes[i].regex
+		}
+
+		return generators.OperationLess(routes[i].path, routes[i].method, routes[j].path, routes[j].method)
+	})
+
+	var hosts []string
+	routesByHost := map[string][]Route{}
+
+	for _, r := range routes {
+		if _, ok := routesByHost[r.host]; !ok {
+			hosts = append(hosts, r.host)
+		}
+
+		routesByHost[r.host] = append(routesByHost[r.host], r.route)
+	}
+
+	routeConfiguration := RouteConfiguration{
+		Name: opts.Service.Name,
+	}
+
+	for _, host := range hosts {
+		name := generators.HostResourceName(opts, host)
+
+		domain := host
+		if domain == "" {
+			domain = "*"
+		}
+
+		routeConfiguration.VirtualHosts = append(routeConfiguration.VirtualHosts, VirtualHost{
+			Name:    name,
+			Domains: []string{domain},
+			Routes:  routesByHost[host],
+		})
+	}
+
+	sort.Slice(routeConfiguration.VirtualHosts, func(i, j int) bool {
+		return routeConfiguration.VirtualHosts[i].Name < routeConfiguration.VirtualHosts[j].Name
+	})
+
+	if err := res.AddObject(routeConfiguration); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func generateRoute(
+	opts *options.Options,
+	cluster, path, method string,
+	params map[string]*openapi3.Parameter,
+	operation *openapi3.Operation,
+) Route {
+	fullPath := strings.TrimSuffix(opts.Path.Base, "/") + path
+
+	res := Route{
+		Name: generators.OperationResourceName(opts.Service.Name, method, path, operation),
+		Match: RouteMatch{
+			Headers: []HeaderMatcher{
+				{
+					Name:        ":method",
+					StringMatch: StringMatcher{Exact: method},
+				},
+			},
+		},
+		Route: RouteAction{
+			Cluster:      cluster,
+			RegexRewrite: generateRegexRewrite(&opts.Path),
+		},
+	}
+
+	if len(params) > 0 {
+		// Envoy uses RE2 grammar for safe regex, the static parts of the path are quoted
+		res.Match.SafeRegex = &RegexMatcher{Regex: pathparams.Regex(fullPath, params, func(_, pattern string) string {
+			return "(" + pattern + ")"
+		})}
+	} else {
+		res.Match.Path = fullPath
+	}
+
+	if timeoutOpts := opts.GetTimeoutOpts(path, method); !reflect.DeepEqual(options.TimeoutOptions{}, timeoutOpts) {
+		if timeoutOpts.RequestTimeout > 0 {
+			res.Route.Timeout = formatDuration(timeoutOpts.RequestTimeout)
+		}
+
+		if timeoutOpts.IdleTimeout > 0 {
+			res.Route.IdleTimeout = formatDuration(timeoutOpts.IdleTimeout)
+		}
+	}
+
+	typedPerFilterConfig := map[string]interface{}{}
+
+	if corsOpts := opts.GetCORSOpts(path, method); !reflect.DeepEqual(options.CORSOptions{}, corsOpts) {
+		typedPerFilterConfig[corsFilterName] = generateCorsPolicy(&corsOpts)
+	}
+
+	if rateLimitOpts := opts.GetRateLimitOpts(path, method); rateLimitOpts.RPS > 0 {
+		typedPerFilterConfig[rateLimitFilterName] = generateLocalRateLimit(&rateLimitOpts)
+	}
+
+	if len(typedPerFilterConfig) > 0 {
+		res.TypedPerFilterConfig = typedPerFilterConfig
+	}
+
+	return res
+}
+
+// generateRegexRewrite returns a regex rewrite that removes path.trim_prefix
+// or replaces path.base with path.rewrite, nil if neither is set
+func generateRegexRewrite(pathOpts *options.PathOptions) *RegexMatchAndSubstitute {
+	var prefix, replacement string
+
+	switch {
+	case pathOpts.TrimPrefix != "":
+		prefix = pathOpts.TrimPrefix
+	case pathOpts.Rewrite != "":
+		prefix = pathOpts.Base
+		replacement = strings.TrimSuffix(pathOpts.Rewrite, "/")
+	default:
+		return nil
+	}
+
+	return &RegexMatchAndSubstitute{
+		Pattern: RegexMatcher{
+			Regex: "^" + regexp.QuoteMeta(strings.TrimSuffix(prefix, "/")) + "/?(.*)$",
+		},
+		Substitution: replacement + `/\1`,
+	}
+}
+
+func generateCorsPolicy(corsOpts *options.CORSOptions) CorsPolicy {
+	res := CorsPolicy{
+		Type:             corsPolicyType,
+		AllowMethods:     strings.Join(corsOpts.Methods, ","),
+		AllowHeaders:     strings.Join(corsOpts.Headers, ","),
+		ExposeHeaders:    strings.Join(corsOpts.ExposeHeaders, ","),
+		AllowCredentials: corsOpts.Credentials,
+	}
+
+	for _, origin := range corsOpts.Origins {
+		if origin == "*" {
+			res.AllowOriginStringMatch = append(res.AllowOriginStringMatch, StringMatcher{SafeRegex: &RegexMatcher{Regex: ".*"}})
+			continue
+		}
+
+		res.AllowOriginStringMatch = append(res.AllowOriginStringMatch, StringMatcher{Exact: origin})
+	}
+
+	if corsOpts.MaxAge > 0 {
+		res.MaxAge = fmt.Sprint(corsOpts.MaxAge)
+	}
+
+	return res
+}
+
+func generateLocalRateLimit(rateLimitOpts *options.RateLimitOptions) LocalRateLimit {
+	// the bucket size is the number of requests allowed in a burst,
+	// which can't be lower than the number of tokens added every second
+	maxTokens := rateLimitOpts.RPS
+	if rateLimitOpts.Burst > maxTokens {
+		maxTokens = rateLimitOpts.Burst
+	}
+
+	// the filter is disabled unless explicitly enabled and enforced for the route
+	enabled := RuntimeFractionalPercent{
+		DefaultValue: FractionalPercent{
+			Numerator:   100,
+			Denominator: "HUNDRED",
+		},
+	}
+
+	filterEnabled, filterEnforced := enabled, enabled
+	filterEnabled.RuntimeKey = "local_rate_limit_enabled"
+	filterEnforced.RuntimeKey = "local_rate_limit_enforced"
+
+	return LocalRateLimit{
+		Type:       localRateLimitType,
+		StatPrefix: "http_local_rate_limiter",
+		TokenBucket: TokenBucket{
+			MaxTokens:     maxTokens,
+			TokensPerFill: rateLimitOpts.RPS,
+			FillInterval:  "1s",
+		},
+		FilterEnabled:  filterEnabled,
+		FilterEnforced: filterEnforced,
+	}
+}
+
+// formatDuration formats the duration as a protobuf JSON Duration, i.e. seconds with an optional fraction, e.g. 0.25s
+func formatDuration(d options.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// Marshal builds suitable output to be used as a file-based route configuration or served by an xDS control plane
+func (g *Generator) Marshal(opts *options.Options, res *generators.Result) (string, error) {
+	var builder strings.Builder
+
+	for _, obj := range res.Objects {
+		if opts.Envoy.Format == "json" {
+			b, err := json.MarshalIndent(obj.Object, "", "  ")
+			if err != nil {
+				return "", fmt.Errorf("unable to marshal RouteConfiguration: %+v: %s", obj.Object, err.Error())
+			}
+
+			builder.Write(b)
+			builder.WriteString("\n")
+
+			continue
+		}
+
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal RouteConfiguration: %+v: %s", obj.Object, err.Error())
+		}
+
+		builder.Write(b)
+	}
+
+	return builder.String(), nil
+}
+
+// Merge merges the route configurations generated for several specs into one,
+// the virtual hosts with the same domains are merged keeping the literal paths ahead of the regex ones
+func (g *Generator) Merge(name string, res *generators.Result) error {
+	key := func(obj *unstructured.Unstructured) (string, bool) {
+		return "", obj.GetKind() == ""
+	}
+
+	merge := func(into, obj *unstructured.Unstructured) error {
+		var merged, routeConfiguration RouteConfiguration
+
+		if err := generators.FromUnstructured(into, &merged); err != nil {
+			return err
+		}
+
+		if err := generators.FromUnstructured(obj, &routeConfiguration); err != nil {
+			return err
+		}
+
+		for _, virtualHost := range routeConfiguration.VirtualHosts {
+			i := findVirtualHost(merged.VirtualHosts, virtualHost.Domains)
+			if i < 0 {
+				merged.VirtualHosts = append(merged.VirtualHosts, virtualHost)
+				continue
+			}
+
+			routes := append(merged.VirtualHosts[i].Routes, virtualHost.Routes...)
+
+			// Envoy evaluates routes in order and the first match wins
+			sort.SliceStable(routes, func(i, j int) bool {
+				return routes[i].Match.SafeRegex == nil && routes[j].Match.SafeRegex != nil
+			})
+
+			merged.VirtualHosts[i].Routes = routes
+		}
+
+		if name != "" {
+			merged.Name = name
+		}
+
+		return generators.UpdateObject(into, merged)
+	}
+
+	// the route configuration isn't a Kubernetes resource, its name is set above rather than in the metadata
+	return res.MergeObjects("", key, merge)
+}
+
+func findVirtualHost(virtualHosts []VirtualHost, domains []string) int {
+	for i, virtualHost := range virtualHosts {
+		if reflect.DeepEqual(virtualHost.Domains, domains) {
+			return i
+		}
+	}
+
+	return -1
+}
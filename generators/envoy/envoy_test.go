@@ -0,0 +1,375 @@
+package envoy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/options"
+	"github.com/kubeshop/kusk-gen/spec"
+)
+
+func TestEnvoy(t *testing.T) {
+	var testCases = []struct {
+		name string
+		spec string
+		res  string
+	}{
+		{
+			name: "virtual host, trim prefix, timeouts, rate limits and CORS",
+			spec: `openapi: 3.0.1
+x-kusk:
+  host: books.example.org
+  path:
+    base: /bookstore
+    trim_prefix: /bookstore
+  timeouts:
+    request_timeout: 10
+    idle_timeout: 60
+  rate_limits:
+    rps: 100
+    burst: 150
+  cors:
+    origins:
+    - "*"
+    methods:
+    - GET
+    - POST
+    max_age: 120
+  service:
+    name: webapp
+    namespace: booksapp
+    port: 7000
+paths:
+  /books:
+    get:
+      operationId: listBooks
+  /books/{id}:
+    x-kusk:
+      timeouts:
+        request_timeout: 2
+    get:
+      operationId: getBook
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+`,
+			res: `name: webapp
+virtual_hosts:
+- domains:
+  - books.example.org
+  name: webapp
+  routes:
+  - match:
+      headers:
+      - name: :method
+        string_match:
+          exact: GET
+      path: /bookstore/books
+    name: webapp-listbooks
+    route:
+      cluster: webapp
+      idle_timeout: 60s
+      regex_rewrite:
+        pattern:
+          regex: ^/bookstore/?(.*)$
+        substitution: /\1
+      timeout: 10s
+    typed_per_filter_config:
+      envoy.filters.http.cors:
+        '@type': type.googleapis.com/envoy.extensions.filters.http.cors.v3.CorsPolicy
+        allow_methods: GET,POST
+        allow_origin_string_match:
+        - safe_regex:
+            regex: .*
+        max_age: "120"
+      envoy.filters.http.local_ratelimit:
+        '@type': type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit
+        filter_enabled:
+          default_value:
+            denominator: HUNDRED
+            numerator: 100
+          runtime_key: local_rate_limit_enabled
+        filter_enforced:
+          default_value:
+            denominator: HUNDRED
+            numerator: 100
+          runtime_key: local_rate_limit_enforced
+        stat_prefix: http_local_rate_limiter
+        token_bucket:
+          fill_interval: 1s
+          max_tokens: 150
+          tokens_per_fill: 100
+  - match:
+      headers:
+      - name: :method
+        string_match:
+          exact: GET
+      safe_regex:
+        regex: /bookstore/books/([0-9]+)
+    name: webapp-getbook
+    route:
+      cluster: webapp
+      idle_timeout: 60s
+      regex_rewrite:
+        pattern:
+          regex: ^/bookstore/?(.*)$
+        substitution: /\1
+      timeout: 2s
+    typed_per_filter_config:
+      envoy.filters.http.cors:
+        '@type': type.googleapis.com/envoy.extensions.filters.http.cors.v3.CorsPolicy
+        allow_methods: GET,POST
+        allow_origin_string_match:
+        - safe_regex:
+            regex: .*
+        max_age: "120"
+      envoy.filters.http.local_ratelimit:
+        '@type': type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit
+        filter_enabled:
+          default_value:
+            denominator: HUNDRED
+            numerator: 100
+          runtime_key: local_rate_limit_enabled
+        filter_enforced:
+          default_value:
+            denominator: HUNDRED
+            numerator: 100
+          runtime_key: local_rate_limit_enforced
+        stat_prefix: http_local_rate_limiter
+        token_bucket:
+          fill_interval: 1s
+          max_tokens: 150
+          tokens_per_fill: 100
+`,
+		},
+		{
+			name: "cluster, JSON output and host override",
+			spec: `openapi: 3.0.1
+x-kusk:
+  envoy:
+    cluster: petstore_cluster
+    format: json
+  service:
+    name: petstore
+    namespace: default
+paths:
+  /pet:
+    get: {}
+    delete:
+      x-kusk:
+        disabled: true
+  /store:
+    x-kusk:
+      host: store.example.org
+    get: {}
+`,
+			res: `{
+  "name": "petstore",
+  "virtual_hosts": [
+    {
+      "domains": [
+        "*"
+      ],
+      "name": "petstore",
+      "routes": [
+        {
+          "match": {
+            "headers": [
+              {
+                "name": ":method",
+                "string_match": {
+                  "exact": "GET"
+                }
+              }
+            ],
+            "path": "/pet"
+          },
+          "name": "petstore-get-pet",
+          "route": {
+            "cluster": "petstore_cluster"
+          }
+        }
+      ]
+    },
+    {
+      "domains": [
+        "store.example.org"
+      ],
+      "name": "petstore-store-example-org",
+      "routes": [
+        {
+          "match": {
+            "headers": [
+              {
+                "name": ":method",
+                "string_match": {
+                  "exact": "GET"
+                }
+              }
+            ],
+            "path": "/store"
+          },
+          "name": "petstore-get-store",
+          "route": {
+            "cluster": "petstore_cluster"
+          }
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			name: "quoted static path segments",
+			spec: `openapi: 3.0.1
+x-kusk:
+  path:
+    base: /api.v1
+  service:
+    name: books
+    namespace: default
+paths:
+  /books+authors/{id}:
+    get:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+`,
+			res: `name: books
+virtual_hosts:
+- domains:
+  - '*'
+  name: books
+  routes:
+  - match:
+      headers:
+      - name: :method
+        string_match:
+          exact: GET
+      safe_regex:
+        regex: /api\.v1/books\+authors/([0-9]+)
+    name: books-get-books-authors-id
+    route:
+      cluster: books
+`,
+		},
+	}
+
+	var gen Generator
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			opts, err := spec.GetOptions(apiSpec)
+			r.NoError(err, "failed to get options")
+
+			res, err := gen.Generate(opts, apiSpec)
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	r := require.New(t)
+
+	specs := []string{`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+x-kusk:
+  host: api.example.org
+  path:
+    base: /books
+  service:
+    name: books
+    namespace: default
+paths:
+  "/{id}":
+    get:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+`, `
+openapi: 3.0.2
+info:
+  title: Authors
+  version: 1.0.0
+x-kusk:
+  host: api.example.org
+  path:
+    base: /authors
+  service:
+    name: authors
+    namespace: default
+paths:
+  "/":
+    get: {}
+`}
+
+	var gen Generator
+	var results []*generators.Result
+
+	for _, s := range specs {
+		apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(s))
+		r.NoError(err, "failed to parse spec")
+
+		opts, err := spec.GetOptions(apiSpec)
+		r.NoError(err, "failed to get options")
+
+		res, err := gen.GenerateObjects(opts, apiSpec)
+		r.NoError(err)
+
+		results = append(results, res)
+	}
+
+	res, err := generators.Merge(&gen, "public-api", results)
+	r.NoError(err)
+
+	actual, err := gen.Marshal(&options.Options{}, res)
+	r.NoError(err)
+	r.Equal(`name: public-api
+virtual_hosts:
+- domains:
+  - api.example.org
+  name: books
+  routes:
+  - match:
+      headers:
+      - name: :method
+        string_match:
+          exact: GET
+      path: /authors/
+    name: authors-get
+    route:
+      cluster: authors
+  - match:
+      headers:
+      - name: :method
+        string_match:
+          exact: GET
+      safe_regex:
+        regex: /books/([^/]+)
+    name: books-get-id
+    route:
+      cluster: books
+`, actual)
+}
@@ -0,0 +1,90 @@
+package envoy
+
+// The types below mirror the subset of the Envoy v3 route configuration API
+// that the generator produces, so that we don't have to depend on the Envoy control plane libraries.
+
+type RouteConfiguration struct {
+	Name         string        `json:"name"`
+	VirtualHosts []VirtualHost `json:"virtual_hosts"`
+}
+
+type VirtualHost struct {
+	Name    string   `json:"name"`
+	Domains []string `json:"domains"`
+	Routes  []Route  `json:"routes"`
+}
+
+type Route struct {
+	Name                 string                 `json:"name,omitempty"`
+	Match                RouteMatch             `json:"match"`
+	Route                RouteAction            `json:"route"`
+	TypedPerFilterConfig map[string]interface{} `json:"typed_per_filter_config,omitempty"`
+}
+
+type RouteMatch struct {
+	Path      string          `json:"path,omitempty"`
+	SafeRegex *RegexMatcher   `json:"safe_regex,omitempty"`
+	Headers   []HeaderMatcher `json:"headers,omitempty"`
+}
+
+type RegexMatcher struct {
+	Regex string `json:"regex"`
+}
+
+type HeaderMatcher struct {
+	Name        string        `json:"name"`
+	StringMatch StringMatcher `json:"string_match"`
+}
+
+type StringMatcher struct {
+	Exact     string        `json:"exact,omitempty"`
+	SafeRegex *RegexMatcher `json:"safe_regex,omitempty"`
+}
+
+type RouteAction struct {
+	Cluster      string                   `json:"cluster"`
+	RegexRewrite *RegexMatchAndSubstitute `json:"regex_rewrite,omitempty"`
+	Timeout      string                   `json:"timeout,omitempty"`
+	IdleTimeout  string                   `json:"idle_timeout,omitempty"`
+}
+
+type RegexMatchAndSubstitute struct {
+	Pattern      RegexMatcher `json:"pattern"`
+	Substitution string       `json:"substitution"`
+}
+
+type CorsPolicy struct {
+	Type string `json:"@type"`
+
+	AllowOriginStringMatch []StringMatcher `json:"allow_origin_string_match,omitempty"`
+	AllowMethods           string          `json:"allow_methods,omitempty"`
+	AllowHeaders           string          `json:"allow_headers,omitempty"`
+	ExposeHeaders          string          `json:"expose_headers,omitempty"`
+	MaxAge                 string          `json:"max_age,omitempty"`
+	AllowCredentials       *bool           `json:"allow_credentials,omitempty"`
+}
+
+type LocalRateLimit struct {
+	Type string `json:"@type"`
+
+	StatPrefix     string                   `json:"stat_prefix"`
+	TokenBucket    TokenBucket              `json:"token_bucket"`
+	FilterEnabled  RuntimeFractionalPercent `json:"filter_enabled"`
+	FilterEnforced RuntimeFractionalPercent `json:"filter_enforced"`
+}
+
+type TokenBucket struct {
+	MaxTokens     uint32 `json:"max_tokens"`
+	TokensPerFill uint32 `json:"tokens_per_fill"`
+	FillInterval  string `json:"fill_interval"`
+}
+
+type RuntimeFractionalPercent struct {
+	DefaultValue FractionalPercent `json:"default_value"`
+	RuntimeKey   string            `json:"runtime_key"`
+}
+
+type FractionalPercent struct {
+	Numerator   uint32 `json:"numerator"`
+	Denominator string `json:"denominator"`
+}
@@ -0,0 +1,335 @@
+package gateway_api
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/generators/pathparams"
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+const (
+	httpRouteAPIVersion = "gateway.networking.k8s.io/v1"
+	httpRouteKind       = "HTTPRoute"
+
+	// maxRulesPerRoute is the maximum number of rules of an HTTPRoute
+	maxRulesPerRoute = 16
+)
+
+var (
+	openApiPathVariableRegex = regexp.MustCompile(`{[^}]+}`)
+)
+
+func init() {
+	generators.Registry["gateway-api"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "gateway-api"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("gateway-api", pflag.ExitOnError)
+
+	fs.String(
+		"gateway_api.gateway_name",
+		"",
+		"the name of the Gateway to attach HTTPRoutes to",
+	)
+
+	fs.String(
+		"gateway_api.gateway_namespace",
+		"",
+		"the namespace of the Gateway, defaults to the namespace of generated resources",
+	)
+
+	fs.String(
+		"gateway_api.section_name",
+		"",
+		"the Gateway listener to attach HTTPRoutes to",
+	)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"path.trim_prefix",
+		"",
+		"a prefix to trim from the URL before forwarding to the upstream Service",
+	)
+
+	fs.String(
+		"path.rewrite",
+		"",
+		"rewrite your base path before forwarding to the upstream service",
+	)
+
+	fs.Bool(
+		"path.split",
+		false,
+		"force Kusk to generate a separate HTTPRoute for each operation",
+	)
+
+	fs.Var(
+		new(options.Duration),
+		"timeouts.request_timeout",
+		"total request timeout, in seconds or as a duration, e.g. 1500ms",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"the Host header value to listen on",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates Kubernetes Gateway API HTTPRoutes for your service"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+// routeRule is an HTTPRoute rule generated for a single operation
+// along with the data needed to group it into an HTTPRoute
+type routeRule struct {
+	name string
+	host string
+	path string
+
+	method string
+	rule   HTTPRouteRule
+	// rewriteSkipped tells that the path rewrite couldn't be applied to the rule
+	rewriteSkipped bool
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	res, err := generators.GenerateObjects(g, opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	res.LogWarnings()
+
+	return res.YAML()
+}
+
+func (g *Generator) GenerateObjects(opts *options.Options, spec *openapi3.T) (*generators.Result, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return nil, fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	if opts.GatewayAPI.GatewayName == "" {
+		return nil, errors.New("gateway_api.gateway_name is required")
+	}
+
+	var rules []routeRule
+
+	for path, pathItem := range spec.Paths {
+		for method, operation := range pathItem.Operations() {
+			if opts.IsOperationDisabled(path, method) {
+				continue
+			}
+
+			rule, rewriteSkipped := generateRule(opts, path, method, pathparams.Find(pathItem, operation))
+
+			rules = append(rules, routeRule{
+				name:           generators.OperationResourceName(opts.Service.Name, method, path, operation),
+				host:           opts.GetHost(path, method),
+				path:           path,
+				method:         method,
+				rule:           rule,
+				rewriteSkipped: rewriteSkipped,
+			})
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return generators.OperationLess(rules[i].path, rules[i].method, rules[j].path, rules[j].method)
+	})
+
+	res := &generators.Result{}
+
+	for _, r := range rules {
+		if r.rewriteSkipped {
+			res.Warn(
+				"%s %s: path.trim_prefix and path.rewrite can't be used with path parameters, "+
+					"as Gateway API only rewrites the prefix of PathPrefix matches, the path is forwarded as is",
+				r.method, r.path,
+			)
+		}
+	}
+
+	var routes []HTTPRoute
+
+	if opts.Path.Split {
+		for _, r := range rules {
+			routes = append(routes, newHTTPRoute(r.name, r.host, opts, []HTTPRouteRule{r.rule}))
+		}
+
+		sort.Slice(routes, func(i, j int) bool {
+			return routes[i].Name < routes[j].Name
+		})
+	} else {
+		// hostnames are set on the HTTPRoute level, so operations with a different host
+		// are grouped into their own HTTPRoute
+		var hosts []string
+		rulesByHost := map[string][]HTTPRouteRule{}
+
+		for _, r := range rules {
+			if _, ok := rulesByHost[r.host]; !ok {
+				hosts = append(hosts, r.host)
+			}
+
+			rulesByHost[r.host] = append(rulesByHost[r.host], r.rule)
+		}
+
+		sort.Slice(hosts, func(i, j int) bool {
+			return generators.HostResourceName(opts, hosts[i]) < generators.HostResourceName(opts, hosts[j])
+		})
+
+		for _, host := range hosts {
+			name := generators.HostResourceName(opts, host)
+			hostRules := rulesByHost[host]
+
+			// the API server rejects HTTPRoutes with more than maxRulesPerRoute rules,
+			// so the rules are spread over several HTTPRoutes named name, name-2, name-3, ...
+			for i := 0; i*maxRulesPerRoute < len(hostRules); i++ {
+				end := (i + 1) * maxRulesPerRoute
+				if end > len(hostRules) {
+					end = len(hostRules)
+				}
+
+				routeName := name
+				if i > 0 {
+					routeName = fmt.Sprintf("%s-%d", name, i+1)
+				}
+
+				routes = append(routes, newHTTPRoute(routeName, host, opts, hostRules[i*maxRulesPerRoute:end]))
+			}
+		}
+	}
+
+	for _, route := range routes {
+		if err := res.AddObject(route); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// generateRule returns the rule of the operation and whether the path rewrite was skipped,
+// as it can't be applied to the paths with parameters
+func generateRule(opts *options.Options, path, method string, params map[string]*openapi3.Parameter) (HTTPRouteRule, bool) {
+	fullPath := strings.TrimSuffix(opts.Path.Base, "/") + path
+
+	rule := HTTPRouteRule{
+		BackendRefs: []HTTPBackendRef{generateBackendRef(opts)},
+	}
+
+	match := HTTPRouteMatch{
+		Method: method,
+	}
+
+	rewriteSkipped := false
+
+	if !openApiPathVariableRegex.MatchString(path) {
+		match.Path = &HTTPPathMatch{Type: pathMatchExact, Value: fullPath}
+
+		if rewritten := opts.Path.RewritePath(fullPath); rewritten != fullPath {
+			rule.Filters = append(rule.Filters, newURLRewriteFilter(&HTTPPathModifier{
+				Type:            fullPathHTTPPathModifier,
+				ReplaceFullPath: rewritten,
+			}))
+		}
+	} else {
+		match.Path = &HTTPPathMatch{
+			Type:  pathMatchRegularExpression,
+			Value: pathparams.Regex(fullPath, params, nil),
+		}
+
+		// URLRewrite is only able to replace a prefix of a PathPrefix match, which would also match
+		// sibling paths, e.g. /pet/findByStatus for /pet/{petId}, while the full path
+		// can't be replaced as it contains the path parameters
+		rewriteSkipped = opts.Path.RewritePath(fullPath) != fullPath
+	}
+
+	rule.Matches = []HTTPRouteMatch{match}
+
+	if timeoutOpts := opts.GetTimeoutOpts(path, method); timeoutOpts.RequestTimeout > 0 {
+		rule.Timeouts = &HTTPRouteTimeouts{
+			Request: timeoutOpts.RequestTimeout.String(),
+		}
+	}
+
+	return rule, rewriteSkipped
+}
+
+func newURLRewriteFilter(modifier *HTTPPathModifier) HTTPRouteFilter {
+	return HTTPRouteFilter{
+		Type: filterURLRewrite,
+		URLRewrite: &HTTPURLRewriteFilter{
+			Path: modifier,
+		},
+	}
+}
+
+func generateBackendRef(opts *options.Options) HTTPBackendRef {
+	ref := HTTPBackendRef{
+		Name: opts.Service.Name,
+		Port: opts.Service.Port,
+	}
+
+	// cross-namespace references require a ReferenceGrant in the Service namespace
+	if opts.Service.Namespace != opts.Namespace {
+		ref.Namespace = opts.Service.Namespace
+	}
+
+	return ref
+}
+
+func newHTTPRoute(name, host string, opts *options.Options, rules []HTTPRouteRule) HTTPRoute {
+	route := HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: httpRouteAPIVersion,
+			Kind:       httpRouteKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: opts.Namespace,
+		},
+		Spec: HTTPRouteSpec{
+			ParentRefs: []ParentReference{
+				{
+					Name:        opts.GatewayAPI.GatewayName,
+					Namespace:   opts.GatewayAPI.GatewayNamespace,
+					SectionName: opts.GatewayAPI.SectionName,
+				},
+			},
+			Rules: rules,
+		},
+	}
+
+	if host != "" {
+		route.Spec.Hostnames = []string{host}
+	}
+
+	return route
+}
@@ -0,0 +1,393 @@
+package gateway_api
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/options"
+	"github.com/kubeshop/kusk-gen/spec"
+)
+
+type testCase struct {
+	name    string
+	options options.Options
+	spec    string
+	res     string
+}
+
+func TestGatewayAPI(t *testing.T) {
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			routes, err := gen.Generate(&testCase.options, spec)
+			r.NoError(err)
+			r.Equal(testCase.res, routes)
+		})
+	}
+}
+
+func TestGatewayAPIGatewayNameRequired(t *testing.T) {
+	r := require.New(t)
+
+	var gen Generator
+
+	_, err := gen.Generate(&options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "petstore",
+		},
+	}, &openapi3.T{})
+	r.EqualError(err, "gateway_api.gateway_name is required")
+}
+
+func TestGatewayAPIRewriteWithPathParameters(t *testing.T) {
+	r := require.New(t)
+
+	var gen Generator
+
+	spec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`openapi: 3.0.1
+paths:
+  /pet/{petId}:
+    get:
+      operationId: getPetById
+`))
+	r.NoError(err, "failed to parse spec")
+
+	res, err := gen.GenerateObjects(&options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "petstore",
+		},
+		GatewayAPI: options.GatewayAPIOptions{
+			GatewayName: "public",
+		},
+		Path: options.PathOptions{
+			Base:       "/petstore/api/v3",
+			TrimPrefix: "/petstore",
+		},
+	}, spec)
+	r.NoError(err)
+	r.Equal([]string{
+		"GET /pet/{petId}: path.trim_prefix and path.rewrite can't be used with path parameters, " +
+			"as Gateway API only rewrites the prefix of PathPrefix matches, the path is forwarded as is",
+	}, res.Warnings)
+
+	yaml, err := res.YAML()
+	r.NoError(err)
+	r.Equal(`---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: default
+spec:
+  parentRefs:
+  - name: public
+  rules:
+  - backendRefs:
+    - name: petstore
+      port: 80
+    matches:
+    - method: GET
+      path:
+        type: RegularExpression
+        value: /petstore/api/v3/pet/[^/]+
+`, yaml)
+}
+
+func TestGatewayAPIMaxRulesPerRoute(t *testing.T) {
+	r := require.New(t)
+
+	var gen Generator
+
+	paths := openapi3.Paths{}
+	for i := 0; i < 20; i++ {
+		paths[fmt.Sprintf("/pet%02d", i)] = &openapi3.PathItem{
+			Get:  &openapi3.Operation{},
+			Post: &openapi3.Operation{},
+		}
+	}
+
+	res, err := gen.GenerateObjects(&options.Options{
+		Service: options.ServiceOptions{
+			Namespace: "default",
+			Name:      "petstore",
+		},
+		GatewayAPI: options.GatewayAPIOptions{
+			GatewayName: "public",
+		},
+	}, &openapi3.T{Paths: paths})
+	r.NoError(err)
+
+	var names []string
+	var rules []int
+	var firstPaths []string
+
+	for _, obj := range res.Objects {
+		var route HTTPRoute
+		r.NoError(generators.FromUnstructured(obj, &route))
+
+		names = append(names, route.Name)
+		rules = append(rules, len(route.Spec.Rules))
+		firstPaths = append(firstPaths, route.Spec.Rules[0].Matches[0].Path.Value)
+	}
+
+	r.Equal([]string{"petstore", "petstore-2", "petstore-3"}, names)
+	r.Equal([]int{16, 16, 8}, rules)
+	// the rules keep the order of the operations across the HTTPRoutes
+	r.Equal([]string{"/pet00", "/pet08", "/pet16"}, firstPaths)
+}
+
+var trueValue = true
+
+var testCases = []testCase{
+	{
+		name: "simple routes",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+				Port:      8080,
+			},
+			GatewayAPI: options.GatewayAPIOptions{
+				GatewayName:      "public",
+				GatewayNamespace: "gateways",
+			},
+			Host: "petstore.example.org",
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pet:
+    put:
+      operationId: updatePet
+    post:
+      operationId: addPet
+  /pet/{petId}:
+    get:
+      operationId: getPetById
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+`,
+		res: `---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: default
+spec:
+  hostnames:
+  - petstore.example.org
+  parentRefs:
+  - name: public
+    namespace: gateways
+  rules:
+  - backendRefs:
+    - name: petstore
+      port: 8080
+    matches:
+    - method: POST
+      path:
+        type: Exact
+        value: /pet
+  - backendRefs:
+    - name: petstore
+      port: 8080
+    matches:
+    - method: PUT
+      path:
+        type: Exact
+        value: /pet
+  - backendRefs:
+    - name: petstore
+      port: 8080
+    matches:
+    - method: GET
+      path:
+        type: RegularExpression
+        value: /pet/[0-9]+
+`,
+	},
+	{
+		name: "trim prefix, timeouts and a disabled operation",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "petstore",
+				Name:      "petstore",
+				Port:      80,
+			},
+			GatewayAPI: options.GatewayAPIOptions{
+				GatewayName: "public",
+				SectionName: "https",
+			},
+			Path: options.PathOptions{
+				Base:       "/petstore/api/v3",
+				TrimPrefix: "/petstore",
+			},
+			Timeouts: options.TimeoutOptions{
+				RequestTimeout: options.Duration(10 * time.Second),
+			},
+			OperationSubOptions: map[string]options.SubOptions{
+				"PUT/pet": {
+					Disabled: &trueValue,
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pet:
+    put:
+      operationId: updatePet
+      x-kusk:
+        disabled: true
+    post:
+      operationId: addPet
+  /store/inventory:
+    get:
+      operationId: getInventory
+`,
+		res: `---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: default
+spec:
+  parentRefs:
+  - name: public
+    sectionName: https
+  rules:
+  - backendRefs:
+    - name: petstore
+      namespace: petstore
+      port: 80
+    filters:
+    - type: URLRewrite
+      urlRewrite:
+        path:
+          replaceFullPath: /api/v3/pet
+          type: ReplaceFullPath
+    matches:
+    - method: POST
+      path:
+        type: Exact
+        value: /petstore/api/v3/pet
+    timeouts:
+      request: 10s
+  - backendRefs:
+    - name: petstore
+      namespace: petstore
+      port: 80
+    filters:
+    - type: URLRewrite
+      urlRewrite:
+        path:
+          replaceFullPath: /api/v3/store/inventory
+          type: ReplaceFullPath
+    matches:
+    - method: GET
+      path:
+        type: Exact
+        value: /petstore/api/v3/store/inventory
+    timeouts:
+      request: 10s
+`,
+	},
+	{
+		name: "split with host override",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "petstore",
+				Port:      80,
+			},
+			GatewayAPI: options.GatewayAPIOptions{
+				GatewayName: "public",
+			},
+			Path: options.PathOptions{
+				Base:  "/",
+				Split: true,
+			},
+			Host: "petstore.example.org",
+			PathSubOptions: map[string]options.SubOptions{
+				"/store": {
+					Host: "store.example.org",
+				},
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /pet:
+    get: {}
+  /store:
+    x-kusk:
+      host: store.example.org
+    get:
+      operationId: getInventory
+`,
+		res: `---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  creationTimestamp: null
+  name: petstore-get-pet
+  namespace: default
+spec:
+  hostnames:
+  - petstore.example.org
+  parentRefs:
+  - name: public
+  rules:
+  - backendRefs:
+    - name: petstore
+      port: 80
+    matches:
+    - method: GET
+      path:
+        type: Exact
+        value: /pet
+---
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  creationTimestamp: null
+  name: petstore-getinventory
+  namespace: default
+spec:
+  hostnames:
+  - store.example.org
+  parentRefs:
+  - name: public
+  rules:
+  - backendRefs:
+    - name: petstore
+      port: 80
+    matches:
+    - method: GET
+      path:
+        type: Exact
+        value: /store
+`,
+	},
+}
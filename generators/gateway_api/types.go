@@ -0,0 +1,81 @@
+package gateway_api
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The types below mirror the subset of the gateway.networking.k8s.io/v1 HTTPRoute API
+// that the generator produces. They are kept local as the upstream module versions
+// compatible with our Kubernetes dependencies predate URLRewrite filters and rule timeouts.
+
+const (
+	pathMatchExact             = "Exact"
+	pathMatchPathPrefix        = "PathPrefix"
+	pathMatchRegularExpression = "RegularExpression"
+
+	filterURLRewrite = "URLRewrite"
+
+	fullPathHTTPPathModifier    = "ReplaceFullPath"
+	prefixMatchHTTPPathModifier = "ReplacePrefixMatch"
+)
+
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HTTPRouteSpec `json:"spec"`
+}
+
+type HTTPRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []HTTPRouteRule   `json:"rules,omitempty"`
+}
+
+type ParentReference struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace,omitempty"`
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+type HTTPRouteRule struct {
+	Matches     []HTTPRouteMatch   `json:"matches,omitempty"`
+	Filters     []HTTPRouteFilter  `json:"filters,omitempty"`
+	BackendRefs []HTTPBackendRef   `json:"backendRefs,omitempty"`
+	Timeouts    *HTTPRouteTimeouts `json:"timeouts,omitempty"`
+}
+
+type HTTPRouteMatch struct {
+	Path   *HTTPPathMatch `json:"path,omitempty"`
+	Method string         `json:"method,omitempty"`
+}
+
+type HTTPPathMatch struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type HTTPRouteFilter struct {
+	Type       string                `json:"type"`
+	URLRewrite *HTTPURLRewriteFilter `json:"urlRewrite,omitempty"`
+}
+
+type HTTPURLRewriteFilter struct {
+	Path *HTTPPathModifier `json:"path,omitempty"`
+}
+
+type HTTPPathModifier struct {
+	Type               string `json:"type"`
+	ReplaceFullPath    string `json:"replaceFullPath,omitempty"`
+	ReplacePrefixMatch string `json:"replacePrefixMatch,omitempty"`
+}
+
+type HTTPBackendRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Port      int32  `json:"port"`
+}
+
+type HTTPRouteTimeouts struct {
+	Request string `json:"request,omitempty"`
+}
@@ -14,5 +14,52 @@ type Interface interface {
 	ShortDescription() string
 	LongDescription() string
 
+	// GenerateObjects returns the generated resources along with any warnings
+	GenerateObjects(options *options.Options, spec *openapi3.T) (*Result, error)
+
+	// Generate returns the generated resources as a string,
+	// derived from the result of GenerateObjects with metadata options applied
 	Generate(options *options.Options, spec *openapi3.T) (string, error)
 }
+
+// ObjectsGenerator generates resources, implemented by every generator
+// and the abstract generators they are built upon
+type ObjectsGenerator interface {
+	GenerateObjects(options *options.Options, spec *openapi3.T) (*Result, error)
+}
+
+// GenerateObjects returns the resources generated by the generator with x-kusk metadata options applied
+// and the warnings about the options added.
+// It is the generation path shared by the CLI and the Generate method of every generator,
+// so that metadata is applied exactly once whichever way the generator is run.
+func GenerateObjects(gen ObjectsGenerator, options *options.Options, spec *openapi3.T) (*Result, error) {
+	res, err := gen.GenerateObjects(options, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Warnings = append(options.Warnings(), res.Warnings...)
+
+	var info *openapi3.Info
+	if spec != nil {
+		info = spec.Info
+	}
+
+	res.ApplyMetadata(&options.Metadata, info)
+
+	return res, nil
+}
+
+// Marshaler is implemented by generators whose output is not a YAML stream of Kubernetes resources
+type Marshaler interface {
+	Marshal(options *options.Options, res *Result) (string, error)
+}
+
+// Marshal returns the string output of the result in the format of the generator
+func Marshal(gen Interface, options *options.Options, res *Result) (string, error) {
+	if marshaler, ok := gen.(Marshaler); ok {
+		return marshaler.Marshal(options, res)
+	}
+
+	return res.YAML()
+}
@@ -0,0 +1,345 @@
+package istio
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/generators/pathparams"
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+const (
+	apiVersion = "networking.istio.io/v1beta1"
+
+	virtualServiceKind  = "VirtualService"
+	destinationRuleKind = "DestinationRule"
+)
+
+var (
+	openApiPathVariableRegex = regexp.MustCompile(`{[^}]+}`)
+)
+
+func init() {
+	generators.Registry["istio"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "istio"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("istio", pflag.ExitOnError)
+
+	fs.StringSlice(
+		"istio.gateways",
+		nil,
+		"Istio Gateways the VirtualService applies to, e.g. istio-system/public-gateway",
+	)
+
+	fs.String(
+		"cluster.cluster_domain",
+		"cluster.local",
+		"kubernetes cluster domain",
+	)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"path.trim_prefix",
+		"",
+		"a prefix to trim from the URL before forwarding to the upstream Service",
+	)
+
+	fs.String(
+		"path.rewrite",
+		"",
+		"rewrite your base path before forwarding to the upstream service",
+	)
+
+	fs.Var(
+		new(options.Duration),
+		"timeouts.request_timeout",
+		"total request timeout, in seconds or as a duration, e.g. 1500ms",
+	)
+
+	fs.Var(
+		new(options.Duration),
+		"timeouts.idle_timeout",
+		"idle connection timeout, in seconds or as a duration, e.g. 2m",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"the Host header value to listen on",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates Istio VirtualService and DestinationRule for your service"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+// route is an HTTP route generated for a single operation
+// along with the data needed to order and group it into a VirtualService
+type route struct {
+	host  string
+	path  string
+	regex bool
+
+	method string
+	http   HTTPRoute
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	res, err := generators.GenerateObjects(g, opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	res.LogWarnings()
+
+	return res.YAML()
+}
+
+func (g *Generator) GenerateObjects(opts *options.Options, spec *openapi3.T) (*generators.Result, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return nil, fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	res := &generators.Result{}
+
+	serviceHost := fmt.Sprintf(
+		"%s.%s.svc.%s",
+		opts.Service.Name,
+		opts.Service.Namespace,
+		opts.Cluster.ClusterDomain,
+	)
+
+	var routes []route
+	idleTimeoutWarned := false
+
+	for path, pathItem := range spec.Paths {
+		for method, operation := range pathItem.Operations() {
+			if opts.IsOperationDisabled(path, method) {
+				continue
+			}
+
+			if !idleTimeoutWarned && opts.GetTimeoutOpts(path, method).IdleTimeout != opts.Timeouts.IdleTimeout {
+				res.Warn("Istio idle timeout is set on the DestinationRule of the upstream Service, path and operation level idle timeouts will be ignored")
+				idleTimeoutWarned = true
+			}
+
+			routes = append(routes, route{
+				host:   opts.GetHost(path, method),
+				path:   path,
+				regex:  openApiPathVariableRegex.MatchString(path),
+				method: method,
+				http:   generateHTTPRoute(opts, serviceHost, path, method, pathItem, operation),
+			})
+		}
+	}
+
+	if len(routes) == 0 {
+		return res, nil
+	}
+
+	// Istio evaluates routes in order and the first match wins,
+	// so literal paths go first to avoid being shadowed by regex matches
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].regex != routes[j].regex {
+			return !routes[i].regex
+		}
+
+		return generators.OperationLess(routes[i].path, routes[i].method, routes[j].path, routes[j].method)
+	})
+
+	// hosts are set on the VirtualService level, so operations with a different host
+	// are grouped into their own VirtualService
+	var hosts []string
+	routesByHost := map[string][]HTTPRoute{}
+
+	for _, r := range routes {
+		if _, ok := routesByHost[r.host]; !ok {
+			hosts = append(hosts, r.host)
+		}
+
+		routesByHost[r.host] = append(routesByHost[r.host], r.http)
+	}
+
+	virtualServices := make([]VirtualService, 0, len(hosts))
+
+	for _, host := range hosts {
+		name := generators.HostResourceName(opts, host)
+
+		vsHost := host
+		if vsHost == "" {
+			vsHost = serviceHost
+		}
+
+		virtualServices = append(virtualServices, VirtualService{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiVersion,
+				Kind:       virtualServiceKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: opts.Namespace,
+			},
+			Spec: VirtualServiceSpec{
+				Hosts:    []string{vsHost},
+				Gateways: opts.Istio.Gateways,
+				HTTP:     routesByHost[host],
+			},
+		})
+	}
+
+	sort.Slice(virtualServices, func(i, j int) bool {
+		return virtualServices[i].Name < virtualServices[j].Name
+	})
+
+	for _, virtualService := range virtualServices {
+		if err := res.AddObject(virtualService); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := res.AddObject(generateDestinationRule(opts, serviceHost)); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func generateHTTPRoute(opts *options.Options, serviceHost, path, method string, pathItem *openapi3.PathItem, operation *openapi3.Operation) HTTPRoute {
+	fullPath := strings.TrimSuffix(opts.Path.Base, "/") + path
+
+	uriMatch := &StringMatch{Exact: fullPath}
+	if openApiPathVariableRegex.MatchString(path) {
+		uriMatch = &StringMatch{Regex: pathparams.Regex(fullPath, pathparams.Find(pathItem, operation), nil)}
+	}
+
+	res := HTTPRoute{
+		Name: generators.OperationResourceName(opts.Service.Name, method, path, operation),
+		Match: []HTTPMatchRequest{
+			{
+				URI:    uriMatch,
+				Method: &StringMatch{Exact: method},
+			},
+		},
+		Route: []HTTPRouteDestination{
+			{
+				Destination: Destination{
+					Host: serviceHost,
+					Port: &PortSelector{Number: opts.Service.Port},
+				},
+			},
+		},
+		Rewrite: generateRewrite(&opts.Path),
+	}
+
+	if timeoutOpts := opts.GetTimeoutOpts(path, method); timeoutOpts.RequestTimeout > 0 {
+		res.Timeout = timeoutOpts.RequestTimeout.String()
+	}
+
+	if corsOpts := opts.GetCORSOpts(path, method); !reflect.DeepEqual(options.CORSOptions{}, corsOpts) {
+		res.CorsPolicy = generateCorsPolicy(&corsOpts)
+	}
+
+	return res
+}
+
+// generateRewrite returns a regex rewrite that removes path.trim_prefix
+// or replaces path.base with path.rewrite, nil if neither is set
+func generateRewrite(pathOpts *options.PathOptions) *HTTPRewrite {
+	var prefix, replacement string
+
+	switch {
+	case pathOpts.TrimPrefix != "":
+		prefix = pathOpts.TrimPrefix
+	case pathOpts.Rewrite != "":
+		prefix = pathOpts.Base
+		replacement = strings.TrimSuffix(pathOpts.Rewrite, "/")
+	default:
+		return nil
+	}
+
+	return &HTTPRewrite{
+		URIRegexRewrite: &RegexRewrite{
+			Match:   "^" + regexp.QuoteMeta(strings.TrimSuffix(prefix, "/")) + "/?(.*)$",
+			Rewrite: replacement + `/\1`,
+		},
+	}
+}
+
+func generateCorsPolicy(corsOpts *options.CORSOptions) *CorsPolicy {
+	res := &CorsPolicy{
+		AllowMethods:     corsOpts.Methods,
+		AllowHeaders:     corsOpts.Headers,
+		ExposeHeaders:    corsOpts.ExposeHeaders,
+		AllowCredentials: corsOpts.Credentials,
+	}
+
+	for _, origin := range corsOpts.Origins {
+		if origin == "*" {
+			res.AllowOrigins = append(res.AllowOrigins, StringMatch{Regex: ".*"})
+			continue
+		}
+
+		res.AllowOrigins = append(res.AllowOrigins, StringMatch{Exact: origin})
+	}
+
+	if corsOpts.MaxAge > 0 {
+		res.MaxAge = fmt.Sprintf("%ds", corsOpts.MaxAge)
+	}
+
+	return res
+}
+
+func generateDestinationRule(opts *options.Options, serviceHost string) DestinationRule {
+	res := DestinationRule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiVersion,
+			Kind:       destinationRuleKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.Service.Name,
+			Namespace: opts.Namespace,
+		},
+		Spec: DestinationRuleSpec{
+			Host: serviceHost,
+		},
+	}
+
+	// idle timeout is a property of the upstream connection pool rather than of a route
+	if opts.Timeouts.IdleTimeout > 0 {
+		res.Spec.TrafficPolicy = &TrafficPolicy{
+			ConnectionPool: &ConnectionPoolSettings{
+				HTTP: &HTTPSettings{
+					IdleTimeout: opts.Timeouts.IdleTimeout.String(),
+				},
+			},
+		}
+	}
+
+	return res
+}
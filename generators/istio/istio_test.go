@@ -0,0 +1,291 @@
+package istio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk-gen/spec"
+)
+
+func TestIstio(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		spec     string
+		res      string
+		warnings []string
+	}{
+		{
+			name: "gateway, trim prefix, timeouts and CORS",
+			spec: `openapi: 3.0.1
+x-kusk:
+  namespace: booksapp
+  host: books.example.org
+  istio:
+    gateways:
+    - istio-system/public
+  path:
+    base: /bookstore
+    trim_prefix: /bookstore
+  timeouts:
+    request_timeout: 10
+    idle_timeout: 60
+  cors:
+    origins:
+    - "*"
+    methods:
+    - GET
+    max_age: 120
+  service:
+    name: webapp
+    namespace: booksapp
+    port: 7000
+paths:
+  /:
+    get: {}
+  /books/{id}:
+    get:
+      operationId: getBook
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+  /books/me:
+    x-kusk:
+      disabled: true
+    get: {}
+`,
+			res: `---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  creationTimestamp: null
+  name: webapp
+  namespace: booksapp
+spec:
+  gateways:
+  - istio-system/public
+  hosts:
+  - books.example.org
+  http:
+  - corsPolicy:
+      allowMethods:
+      - GET
+      allowOrigins:
+      - regex: .*
+      maxAge: 120s
+    match:
+    - method:
+        exact: GET
+      uri:
+        exact: /bookstore/
+    name: webapp-get
+    rewrite:
+      uriRegexRewrite:
+        match: ^/bookstore/?(.*)$
+        rewrite: /\1
+    route:
+    - destination:
+        host: webapp.booksapp.svc.cluster.local
+        port:
+          number: 7000
+    timeout: 10s
+  - corsPolicy:
+      allowMethods:
+      - GET
+      allowOrigins:
+      - regex: .*
+      maxAge: 120s
+    match:
+    - method:
+        exact: GET
+      uri:
+        regex: /bookstore/books/[0-9]+
+    name: webapp-getbook
+    rewrite:
+      uriRegexRewrite:
+        match: ^/bookstore/?(.*)$
+        rewrite: /\1
+    route:
+    - destination:
+        host: webapp.booksapp.svc.cluster.local
+        port:
+          number: 7000
+    timeout: 10s
+---
+apiVersion: networking.istio.io/v1beta1
+kind: DestinationRule
+metadata:
+  creationTimestamp: null
+  name: webapp
+  namespace: booksapp
+spec:
+  host: webapp.booksapp.svc.cluster.local
+  trafficPolicy:
+    connectionPool:
+      http:
+        idleTimeout: 60s
+`,
+		},
+		{
+			name: "mesh routes with path level host override",
+			spec: `openapi: 3.0.1
+x-kusk:
+  service:
+    name: petstore
+    namespace: default
+paths:
+  /pet:
+    post:
+      operationId: addPet
+  /store:
+    x-kusk:
+      host: store.example.org
+      timeouts:
+        request_timeout: 5
+    get:
+      operationId: getInventory
+`,
+			res: `---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: default
+spec:
+  hosts:
+  - petstore.default.svc.cluster.local
+  http:
+  - match:
+    - method:
+        exact: POST
+      uri:
+        exact: /pet
+    name: petstore-addpet
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  creationTimestamp: null
+  name: petstore-store-example-org
+  namespace: default
+spec:
+  hosts:
+  - store.example.org
+  http:
+  - match:
+    - method:
+        exact: GET
+      uri:
+        exact: /store
+    name: petstore-getinventory
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+    timeout: 5s
+---
+apiVersion: networking.istio.io/v1beta1
+kind: DestinationRule
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: default
+spec:
+  host: petstore.default.svc.cluster.local
+`,
+		},
+		{
+			name: "base path quoted in regexes and ignored idle timeout override",
+			spec: `openapi: 3.0.1
+x-kusk:
+  path:
+    base: /api.v1
+  timeouts:
+    idle_timeout: 60
+  service:
+    name: petstore
+    namespace: default
+paths:
+  /pet/{petId}:
+    x-kusk:
+      timeouts:
+        idle_timeout: 5
+    get:
+      operationId: getPetById
+`,
+			res: `---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: default
+spec:
+  hosts:
+  - petstore.default.svc.cluster.local
+  http:
+  - match:
+    - method:
+        exact: GET
+      uri:
+        regex: /api\.v1/pet/[^/]+
+    name: petstore-getpetbyid
+    route:
+    - destination:
+        host: petstore.default.svc.cluster.local
+        port:
+          number: 80
+---
+apiVersion: networking.istio.io/v1beta1
+kind: DestinationRule
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: default
+spec:
+  host: petstore.default.svc.cluster.local
+  trafficPolicy:
+    connectionPool:
+      http:
+        idleTimeout: 60s
+`,
+			warnings: []string{
+				"Istio idle timeout is set on the DestinationRule of the upstream Service, path and operation level idle timeouts will be ignored",
+			},
+		},
+	}
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			opts, err := spec.GetOptions(apiSpec)
+			r.NoError(err, "failed to get options")
+
+			res, err := gen.GenerateObjects(opts, apiSpec)
+			r.NoError(err)
+			r.Equal(testCase.warnings, res.Warnings)
+
+			yaml, err := res.YAML()
+			r.NoError(err)
+			r.Equal(testCase.res, yaml)
+		})
+	}
+}
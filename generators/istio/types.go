@@ -0,0 +1,96 @@
+package istio
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The types below mirror the subset of the networking.istio.io/v1beta1 API
+// that the generator produces, so that we don't have to depend on the Istio client libraries.
+
+type VirtualService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VirtualServiceSpec `json:"spec"`
+}
+
+type VirtualServiceSpec struct {
+	Hosts    []string    `json:"hosts"`
+	Gateways []string    `json:"gateways,omitempty"`
+	HTTP     []HTTPRoute `json:"http,omitempty"`
+}
+
+type HTTPRoute struct {
+	Name       string                 `json:"name,omitempty"`
+	Match      []HTTPMatchRequest     `json:"match,omitempty"`
+	Rewrite    *HTTPRewrite           `json:"rewrite,omitempty"`
+	Route      []HTTPRouteDestination `json:"route"`
+	Timeout    string                 `json:"timeout,omitempty"`
+	CorsPolicy *CorsPolicy            `json:"corsPolicy,omitempty"`
+}
+
+type HTTPMatchRequest struct {
+	URI    *StringMatch `json:"uri,omitempty"`
+	Method *StringMatch `json:"method,omitempty"`
+}
+
+type StringMatch struct {
+	Exact  string `json:"exact,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Regex  string `json:"regex,omitempty"`
+}
+
+type HTTPRewrite struct {
+	URIRegexRewrite *RegexRewrite `json:"uriRegexRewrite,omitempty"`
+}
+
+type RegexRewrite struct {
+	Match   string `json:"match"`
+	Rewrite string `json:"rewrite"`
+}
+
+type HTTPRouteDestination struct {
+	Destination Destination `json:"destination"`
+}
+
+type Destination struct {
+	Host string        `json:"host"`
+	Port *PortSelector `json:"port,omitempty"`
+}
+
+type PortSelector struct {
+	Number int32 `json:"number"`
+}
+
+type CorsPolicy struct {
+	AllowOrigins     []StringMatch `json:"allowOrigins,omitempty"`
+	AllowMethods     []string      `json:"allowMethods,omitempty"`
+	AllowHeaders     []string      `json:"allowHeaders,omitempty"`
+	ExposeHeaders    []string      `json:"exposeHeaders,omitempty"`
+	MaxAge           string        `json:"maxAge,omitempty"`
+	AllowCredentials *bool         `json:"allowCredentials,omitempty"`
+}
+
+type DestinationRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DestinationRuleSpec `json:"spec"`
+}
+
+type DestinationRuleSpec struct {
+	Host          string         `json:"host"`
+	TrafficPolicy *TrafficPolicy `json:"trafficPolicy,omitempty"`
+}
+
+type TrafficPolicy struct {
+	ConnectionPool *ConnectionPoolSettings `json:"connectionPool,omitempty"`
+}
+
+type ConnectionPoolSettings struct {
+	HTTP *HTTPSettings `json:"http,omitempty"`
+}
+
+type HTTPSettings struct {
+	IdleTimeout string `json:"idleTimeout,omitempty"`
+}
@@ -0,0 +1,472 @@
+package kong
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/pflag"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/generators/pathparams"
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+const (
+	ingressAPIVersion = "networking.k8s.io/v1"
+	ingressKind       = "Ingress"
+
+	kongAPIVersion = "configuration.konghq.com/v1"
+	kongPluginKind = "KongPlugin"
+
+	pluginsAnnotationKey        = "konghq.com/plugins"
+	methodsAnnotationKey        = "konghq.com/methods"
+	stripPathAnnotationKey      = "konghq.com/strip-path"
+	rewriteAnnotationKey        = "konghq.com/rewrite"
+	connectTimeoutAnnotationKey = "konghq.com/connect-timeout"
+	readTimeoutAnnotationKey    = "konghq.com/read-timeout"
+	writeTimeoutAnnotationKey   = "konghq.com/write-timeout"
+)
+
+var (
+	ingressClassName           = "kong"
+	pathTypePrefix             = v1.PathTypePrefix
+	pathTypeExact              = v1.PathTypeExact
+	pathTypeImplementationSpec = v1.PathTypeImplementationSpecific
+
+	openApiPathVariableRegex = regexp.MustCompile(`{[^}]+}`)
+)
+
+func init() {
+	generators.Registry["kong"] = &Generator{}
+}
+
+type Generator struct{}
+
+func (g *Generator) Cmd() string {
+	return "kong"
+}
+
+func (g *Generator) Flags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("kong", pflag.ExitOnError)
+
+	fs.String(
+		"path.base",
+		"/",
+		"a base path for Service endpoints",
+	)
+
+	fs.String(
+		"path.trim_prefix",
+		"",
+		"a prefix to trim from the URL before forwarding to the upstream Service",
+	)
+
+	fs.Bool(
+		"path.split",
+		false,
+		"force Kusk to generate a separate Ingress for each operation",
+	)
+
+	fs.String(
+		"host",
+		"",
+		"an Ingress Host to listen on",
+	)
+
+	fs.Uint32(
+		"rate_limits.rps",
+		0,
+		"request per second rate limit",
+	)
+
+	fs.Var(
+		new(options.Duration),
+		"timeouts.request_timeout",
+		"total request timeout, in seconds or as a duration, e.g. 1500ms",
+	)
+
+	return fs
+}
+
+func (g *Generator) ShortDescription() string {
+	return "Generates Kong Ingress Controller resources"
+}
+
+func (g *Generator) LongDescription() string {
+	return g.ShortDescription()
+}
+
+// plugins collects KongPlugins referenced by the generated Ingresses, deduplicated by name
+type plugins map[string]KongPlugin
+
+func (p plugins) add(plugin KongPlugin) string {
+	p[plugin.Name] = plugin
+	return plugin.Name
+}
+
+func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	res, err := generators.GenerateObjects(g, opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	res.LogWarnings()
+
+	return res.YAML()
+}
+
+func (g *Generator) GenerateObjects(opts *options.Options, spec *openapi3.T) (*generators.Result, error) {
+	if err := opts.FillDefaultsAndValidate(); err != nil {
+		return nil, fmt.Errorf("failed to validate opts: %w", err)
+	}
+
+	res := &generators.Result{}
+
+	warnUnsupported(res, opts, spec)
+
+	ingresses := make([]v1.Ingress, 0)
+	kongPlugins := plugins{}
+
+	if shouldSplit(opts, spec) {
+		for path, pathItem := range spec.Paths {
+			for method, operation := range pathItem.Operations() {
+				if opts.IsOperationDisabled(path, method) {
+					continue
+				}
+
+				name := generators.OperationResourceName(opts.Service.Name, method, path, operation)
+				fullPath := strings.TrimSuffix(opts.Path.Base, "/") + path
+
+				annotations := map[string]string{
+					methodsAnnotationKey: method,
+				}
+
+				ingressPath, pathType := fullPath, pathTypeExact
+				if openApiPathVariableRegex.MatchString(path) || opts.Path.TrimPrefix != "" {
+					ingressPath, pathType = generateRegexPath(fullPath, opts.Path.TrimPrefix, pathparams.Find(pathItem, operation)), pathTypeImplementationSpec
+				}
+
+				if opts.Path.TrimPrefix != "" && strings.HasPrefix(fullPath, opts.Path.TrimPrefix) {
+					annotations[rewriteAnnotationKey] = "$1"
+				}
+
+				corsOpts := opts.GetCORSOpts(path, method)
+				rateLimitOpts := opts.GetRateLimitOpts(path, method)
+
+				pluginNames := generatePlugins(kongPlugins, opts, name, corsOpts, rateLimitOpts)
+				if len(pluginNames) > 0 {
+					annotations[pluginsAnnotationKey] = strings.Join(pluginNames, ", ")
+				}
+
+				ingresses = append(ingresses, newIngressResource(
+					name,
+					opts.Namespace,
+					ingressPath,
+					pathType,
+					annotations,
+					&opts.Service,
+					opts.GetHost(path, method),
+				))
+			}
+		}
+	} else if !opts.Disabled {
+		name := fmt.Sprintf("%s-ingress", opts.Service.Name)
+		annotations := map[string]string{}
+
+		ingressPath := opts.Path.Base
+		pathType := pathTypePrefix
+
+		if trimPrefix := opts.Path.TrimPrefix; trimPrefix != "" && strings.HasPrefix(opts.Path.Base, trimPrefix) {
+			if strings.TrimSuffix(trimPrefix, "/") == strings.TrimSuffix(opts.Path.Base, "/") {
+				annotations[stripPathAnnotationKey] = "true"
+			} else {
+				// capture the rest of the base path along with anything following it
+				rest := strings.TrimSuffix(strings.TrimPrefix(opts.Path.Base, trimPrefix), "/")
+				ingressPath, pathType = "/~"+regexp.QuoteMeta(trimPrefix)+"("+regexp.QuoteMeta(rest)+"(?:/.*)?)$", pathTypeImplementationSpec
+				annotations[rewriteAnnotationKey] = "$1"
+			}
+		}
+
+		pluginNames := generatePlugins(kongPlugins, opts, name, opts.CORS, opts.RateLimits)
+		if len(pluginNames) > 0 {
+			annotations[pluginsAnnotationKey] = strings.Join(pluginNames, ", ")
+		}
+
+		ingresses = append(ingresses, newIngressResource(
+			name,
+			opts.Namespace,
+			ingressPath,
+			pathType,
+			annotations,
+			&opts.Service,
+			opts.Host,
+		))
+	}
+
+	// sort the ingresses for the output to be stable
+	sort.Slice(ingresses, func(i, j int) bool {
+		return ingresses[i].Name < ingresses[j].Name
+	})
+
+	pluginsList := make([]KongPlugin, 0, len(kongPlugins))
+	for _, plugin := range kongPlugins {
+		pluginsList = append(pluginsList, plugin)
+	}
+
+	sort.Slice(pluginsList, func(i, j int) bool {
+		return pluginsList[i].Name < pluginsList[j].Name
+	})
+
+	for _, plugin := range pluginsList {
+		if err := res.AddObject(plugin); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ingress := range ingresses {
+		if err := res.AddObject(ingress); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// generatePlugins creates KongPlugins for the given CORS and rate limit options
+// and returns the names of plugins to reference from the Ingress
+func generatePlugins(
+	kongPlugins plugins,
+	opts *options.Options,
+	ingressName string,
+	corsOpts options.CORSOptions,
+	rateLimitOpts options.RateLimitOptions,
+) []string {
+	var res []string
+
+	if !reflect.DeepEqual(options.CORSOptions{}, corsOpts) {
+		name := opts.Service.Name + "-cors"
+		if !reflect.DeepEqual(opts.CORS, corsOpts) {
+			name = ingressName + "-cors"
+		}
+
+		res = append(res, kongPlugins.add(newCORSPlugin(name, opts.Namespace, &corsOpts)))
+	}
+
+	if rateLimitOpts.RPS != 0 {
+		var name string
+
+		switch {
+		case rateLimitOpts.Group != "":
+			// operations within a group share a single plugin
+			name = generators.SanitizeName(opts.Service.Name + "-ratelimit-" + rateLimitOpts.Group)
+
+			// rate limit already configured within this group, keep the lower limit
+			if existing, ok := kongPlugins[name]; ok {
+				if existing.Config.(rateLimitingPluginConfig).Second < rateLimitOpts.RPS {
+					res = append(res, name)
+					break
+				}
+			}
+
+			res = append(res, kongPlugins.add(newRateLimitingPlugin(name, opts.Namespace, &rateLimitOpts)))
+		case reflect.DeepEqual(opts.RateLimits, rateLimitOpts):
+			name = opts.Service.Name + "-ratelimit"
+			res = append(res, kongPlugins.add(newRateLimitingPlugin(name, opts.Namespace, &rateLimitOpts)))
+		default:
+			name = ingressName + "-ratelimit"
+			res = append(res, kongPlugins.add(newRateLimitingPlugin(name, opts.Namespace, &rateLimitOpts)))
+		}
+	}
+
+	return res
+}
+
+func newCORSPlugin(name, namespace string, corsOpts *options.CORSOptions) KongPlugin {
+	return KongPlugin{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kongAPIVersion,
+			Kind:       kongPluginKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		PluginName: "cors",
+		Config: corsPluginConfig{
+			Origins:        corsOpts.Origins,
+			Methods:        corsOpts.Methods,
+			Headers:        corsOpts.Headers,
+			ExposedHeaders: corsOpts.ExposeHeaders,
+			Credentials:    corsOpts.Credentials,
+			MaxAge:         corsOpts.MaxAge,
+		},
+	}
+}
+
+func newRateLimitingPlugin(name, namespace string, rateLimitOpts *options.RateLimitOptions) KongPlugin {
+	return KongPlugin{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kongAPIVersion,
+			Kind:       kongPluginKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		PluginName: "rate-limiting",
+		Config: rateLimitingPluginConfig{
+			Second: rateLimitOpts.RPS,
+			Policy: "local",
+		},
+	}
+}
+
+// generateRegexPath returns a Kong regex path for the given path with the path parameters replaced,
+// capturing everything after trimPrefix so it could be used in the rewrite annotation
+func generateRegexPath(path, trimPrefix string, params map[string]*openapi3.Parameter) string {
+	if trimPrefix != "" && strings.HasPrefix(path, trimPrefix) {
+		return "/~" + regexp.QuoteMeta(trimPrefix) + "(" + pathparams.Regex(strings.TrimPrefix(path, trimPrefix), params, nil) + ")$"
+	}
+
+	return "/~" + pathparams.Regex(path, params, nil) + "$"
+}
+
+func newIngressResource(
+	name,
+	namespace,
+	path string,
+	pathType v1.PathType,
+	annotations map[string]string,
+	serviceOpts *options.ServiceOptions,
+	host string,
+) v1.Ingress {
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	return v1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ingressAPIVersion,
+			Kind:       ingressKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		Spec: v1.IngressSpec{
+			IngressClassName: &ingressClassName,
+			Rules: []v1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: v1.IngressRuleValue{
+						HTTP: &v1.HTTPIngressRuleValue{
+							Paths: []v1.HTTPIngressPath{
+								{
+									PathType: &pathType,
+									Path:     path,
+									Backend: v1.IngressBackend{
+										Service: &v1.IngressServiceBackend{
+											Name: serviceOpts.Name,
+											Port: v1.ServiceBackendPort{
+												Number: serviceOpts.Port,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func shouldSplit(opts *options.Options, spec *openapi3.T) bool {
+	if opts.Path.Split {
+		return true
+	}
+
+	for path, pathItem := range spec.Paths {
+		if opts.IsPathDisabled(path) != opts.Disabled {
+			return true
+		}
+
+		for method := range pathItem.Operations() {
+			if opts.IsOperationDisabled(path, method) != opts.Disabled {
+				return true
+			}
+
+			if !reflect.DeepEqual(opts.CORS, opts.GetCORSOpts(path, method)) ||
+				!reflect.DeepEqual(opts.RateLimits, opts.GetRateLimitOpts(path, method)) ||
+				opts.GetHost(path, method) != opts.Host {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// warnUnsupported warns about options that can't be expressed with Kong Ingress Controller resources
+func warnUnsupported(res *generators.Result, opts *options.Options, spec *openapi3.T) {
+	burstWarned, pathTimeoutsWarned := false, false
+	split := shouldSplit(opts, spec)
+	groupOperations := map[string]int{}
+
+	for path, pathItem := range spec.Paths {
+		for method := range pathItem.Operations() {
+			if !burstWarned && opts.GetRateLimitOpts(path, method).Burst != 0 {
+				res.Warn("Kong rate-limiting plugin does not support burst. It will be ignored")
+				burstWarned = true
+			}
+
+			if !pathTimeoutsWarned && !reflect.DeepEqual(opts.Timeouts, opts.GetTimeoutOpts(path, method)) {
+				res.Warn("Kong timeouts are set on the upstream Service, path and operation level timeouts will be ignored")
+				pathTimeoutsWarned = true
+			}
+
+			if group := opts.GetRateLimitOpts(path, method).Group; split && group != "" && !opts.IsOperationDisabled(path, method) {
+				groupOperations[group]++
+			}
+		}
+	}
+
+	// Kong Ingress Controller reads timeouts from the annotations of the upstream Service only,
+	// which isn't generated, so the annotations are left to be set on the Service
+	if opts.Timeouts.RequestTimeout > 0 {
+		res.Warn(
+			"Kong timeouts are read from the upstream Service, annotate Service %s/%s with %s, %s and %s set to %d",
+			opts.Service.Namespace,
+			opts.Service.Name,
+			connectTimeoutAnnotationKey,
+			readTimeoutAnnotationKey,
+			writeTimeoutAnnotationKey,
+			opts.Timeouts.RequestTimeout.Milliseconds(),
+		)
+	}
+
+	// Kong rate-limiting plugin keeps a counter per route, even if the routes reference the same KongPlugin
+	var groups []string
+	for group, operations := range groupOperations {
+		if operations > 1 {
+			groups = append(groups, group)
+		}
+	}
+
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		res.Warn(
+			"Kong rate-limiting plugin counts requests per route, operations in rate limit group %s share the limit but not the counter",
+			group,
+		)
+	}
+}
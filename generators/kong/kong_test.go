@@ -0,0 +1,311 @@
+package kong
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk-gen/spec"
+)
+
+func TestKong(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		spec     string
+		res      string
+		warnings []string
+	}{
+		{
+			name: "single ingress with global plugins and timeouts",
+			spec: `openapi: 3.0.1
+x-kusk:
+  namespace: booksapp
+  host: books.example.org
+  path:
+    base: /bookstore
+    trim_prefix: /bookstore
+  timeouts:
+    request_timeout: 10
+  cors:
+    origins:
+    - http://foo.example
+    methods:
+    - GET
+    max_age: 120
+  rate_limits:
+    rps: 100
+  service:
+    name: webapp
+    namespace: booksapp
+    port: 7000
+paths:
+  /:
+    get: {}
+  /books/{id}:
+    get:
+      operationId: getBook
+`,
+			res: `---
+apiVersion: configuration.konghq.com/v1
+config:
+  max_age: 120
+  methods:
+  - GET
+  origins:
+  - http://foo.example
+kind: KongPlugin
+metadata:
+  creationTimestamp: null
+  name: webapp-cors
+  namespace: booksapp
+plugin: cors
+---
+apiVersion: configuration.konghq.com/v1
+config:
+  policy: local
+  second: 100
+kind: KongPlugin
+metadata:
+  creationTimestamp: null
+  name: webapp-ratelimit
+  namespace: booksapp
+plugin: rate-limiting
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    konghq.com/plugins: webapp-cors, webapp-ratelimit
+    konghq.com/strip-path: "true"
+  creationTimestamp: null
+  name: webapp-ingress
+  namespace: booksapp
+spec:
+  ingressClassName: kong
+  rules:
+  - host: books.example.org
+    http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 7000
+        path: /bookstore
+        pathType: Prefix
+status:
+  loadBalancer: {}
+`,
+			warnings: []string{
+				"Kong timeouts are read from the upstream Service, annotate Service booksapp/webapp with " +
+					"konghq.com/connect-timeout, konghq.com/read-timeout and konghq.com/write-timeout set to 10000",
+			},
+		},
+		{
+			name: "ingress per operation with grouped rate limits",
+			spec: `openapi: 3.0.1
+x-kusk:
+  namespace: booksapp
+  path:
+    base: /bookstore
+    trim_prefix: /bookstore
+  service:
+    name: webapp
+    namespace: booksapp
+    port: 7000
+paths:
+  /authors:
+    x-kusk:
+      rate_limits:
+        rps: 10
+        group: books
+    get:
+      operationId: listAuthors
+  /books:
+    x-kusk:
+      rate_limits:
+        rps: 100
+        group: books
+    get:
+      operationId: listBooks
+  /books/{id}:
+    x-kusk:
+      cors:
+        origins:
+        - "*"
+    get:
+      operationId: getBook
+    delete:
+      x-kusk:
+        disabled: true
+`,
+			res: `---
+apiVersion: configuration.konghq.com/v1
+config:
+  origins:
+  - '*'
+kind: KongPlugin
+metadata:
+  creationTimestamp: null
+  name: webapp-getbook-cors
+  namespace: booksapp
+plugin: cors
+---
+apiVersion: configuration.konghq.com/v1
+config:
+  policy: local
+  second: 10
+kind: KongPlugin
+metadata:
+  creationTimestamp: null
+  name: webapp-ratelimit-books
+  namespace: booksapp
+plugin: rate-limiting
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    konghq.com/methods: GET
+    konghq.com/plugins: webapp-getbook-cors
+    konghq.com/rewrite: $1
+  creationTimestamp: null
+  name: webapp-getbook
+  namespace: booksapp
+spec:
+  ingressClassName: kong
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 7000
+        path: /~/bookstore(/books/[^/]+)$
+        pathType: ImplementationSpecific
+status:
+  loadBalancer: {}
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    konghq.com/methods: GET
+    konghq.com/plugins: webapp-ratelimit-books
+    konghq.com/rewrite: $1
+  creationTimestamp: null
+  name: webapp-listauthors
+  namespace: booksapp
+spec:
+  ingressClassName: kong
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 7000
+        path: /~/bookstore(/authors)$
+        pathType: ImplementationSpecific
+status:
+  loadBalancer: {}
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    konghq.com/methods: GET
+    konghq.com/plugins: webapp-ratelimit-books
+    konghq.com/rewrite: $1
+  creationTimestamp: null
+  name: webapp-listbooks
+  namespace: booksapp
+spec:
+  ingressClassName: kong
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 7000
+        path: /~/bookstore(/books)$
+        pathType: ImplementationSpecific
+status:
+  loadBalancer: {}
+`,
+			warnings: []string{
+				"Kong rate-limiting plugin counts requests per route, operations in rate limit group books share the limit but not the counter",
+			},
+		},
+		{
+			name: "single ingress with trim prefix of the base path",
+			spec: `openapi: 3.0.1
+x-kusk:
+  namespace: booksapp
+  path:
+    base: /api.v1/books
+    trim_prefix: /api.v1
+  service:
+    name: webapp
+    namespace: booksapp
+    port: 7000
+paths:
+  /:
+    get:
+      operationId: listBooks
+`,
+			res: `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    konghq.com/rewrite: $1
+  creationTimestamp: null
+  name: webapp-ingress
+  namespace: booksapp
+spec:
+  ingressClassName: kong
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: webapp
+            port:
+              number: 7000
+        path: /~/api\.v1(/books(?:/.*)?)$
+        pathType: ImplementationSpecific
+status:
+  loadBalancer: {}
+`,
+		},
+	}
+
+	var gen Generator
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(testCase.spec))
+			r.NoError(err, "failed to parse spec")
+
+			opts, err := spec.GetOptions(apiSpec)
+			r.NoError(err, "failed to get options")
+
+			res, err := gen.GenerateObjects(opts, apiSpec)
+			r.NoError(err)
+			r.Equal(testCase.warnings, res.Warnings)
+
+			yaml, err := res.YAML()
+			r.NoError(err)
+			r.Equal(testCase.res, yaml)
+		})
+	}
+}
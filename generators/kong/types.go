@@ -0,0 +1,30 @@
+package kong
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The types below mirror the subset of the configuration.konghq.com/v1 API
+// that the generator produces, so that we don't have to depend on the Kong Ingress Controller libraries.
+
+type KongPlugin struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	PluginName string      `json:"plugin"`
+	Config     interface{} `json:"config,omitempty"`
+}
+
+type corsPluginConfig struct {
+	Origins        []string `json:"origins,omitempty"`
+	Methods        []string `json:"methods,omitempty"`
+	Headers        []string `json:"headers,omitempty"`
+	ExposedHeaders []string `json:"exposed_headers,omitempty"`
+	Credentials    *bool    `json:"credentials,omitempty"`
+	MaxAge         int      `json:"max_age,omitempty"`
+}
+
+type rateLimitingPluginConfig struct {
+	Second uint32 `json:"second"`
+	Policy string `json:"policy"`
+}
@@ -2,12 +2,10 @@ package linkerd
 
 import (
 	"fmt"
-	"reflect"
 	"sort"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
-	"github.com/ghodss/yaml"
 	"github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha2"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/profiles"
@@ -43,10 +41,10 @@ func (g *Generator) Flags() *pflag.FlagSet {
 		"a base prefix for Service endpoints",
 	)
 
-	fs.Uint32(
+	fs.Var(
+		new(options.Duration),
 		"timeouts.request_timeout",
-		0,
-		"total request timeout (seconds)",
+		"total request timeout, in seconds or as a duration, e.g. 1500ms",
 	)
 
 	return fs
@@ -61,13 +59,26 @@ func (g *Generator) LongDescription() string {
 }
 
 func (g *Generator) Generate(options *options.Options, spec *openapi3.T) (string, error) {
+	res, err := generators.GenerateObjects(g, options, spec)
+	if err != nil {
+		return "", err
+	}
+
+	res.LogWarnings()
+
+	return res.YAML()
+}
+
+func (g *Generator) GenerateObjects(options *options.Options, spec *openapi3.T) (*generators.Result, error) {
 	if err := options.FillDefaultsAndValidate(); err != nil {
-		return "", fmt.Errorf("failed to validate options: %w", err)
+		return nil, fmt.Errorf("failed to validate options: %w", err)
 	}
 
+	res := &generators.Result{}
+
 	spSpec := g.generateServiceProfileSpec(options, spec)
 	if len(spSpec.Routes) == 0 {
-		return "", nil
+		return res, nil
 	}
 
 	profile := &v1alpha2.ServiceProfile{
@@ -87,9 +98,11 @@ func (g *Generator) Generate(options *options.Options, spec *openapi3.T) (string
 		Spec: spSpec,
 	}
 
-	b, err := yaml.Marshal(profile)
+	if err := res.AddObject(profile); err != nil {
+		return nil, err
+	}
 
-	return string(b), err
+	return res, nil
 }
 
 func (g *Generator) generateServiceProfileSpec(options *options.Options, spec *openapi3.T) v1alpha2.ServiceProfileSpec {
@@ -113,38 +126,24 @@ func (g *Generator) generateServiceProfileSpec(options *options.Options, spec *o
 }
 
 func generateRouteSpec(method, path string, opts *options.Options) *v1alpha2.RouteSpec {
-	path = strings.TrimSuffix(opts.Path.Base, "/") + "/" + strings.TrimPrefix(path, "/")
+	fullPath := strings.TrimSuffix(opts.Path.Base, "/") + "/" + strings.TrimPrefix(path, "/")
 
 	res := &v1alpha2.RouteSpec{
-		Name: fmt.Sprintf("%s %s", method, path),
+		Name: fmt.Sprintf("%s %s", method, fullPath),
 		Condition: &v1alpha2.RequestMatch{
-			PathRegex: profiles.PathToRegex(path),
+			PathRegex: profiles.PathToRegex(fullPath),
 			Method:    method,
 		},
 	}
 
-	// global timeouts are defined, use them
-	if !reflect.DeepEqual(options.TimeoutOptions{}, opts.Timeouts) {
-		res.Timeout = formatTimeout(opts.Timeouts.RequestTimeout)
-	}
-
-	// non-zero path-level timeouts are defined, use them
-	if pathSubOpts, ok := opts.PathSubOptions[path]; ok {
-		if !reflect.DeepEqual(options.TimeoutOptions{}, pathSubOpts.Timeouts) {
-			res.Timeout = formatTimeout(pathSubOpts.Timeouts.RequestTimeout)
-		}
-	}
-
-	// non-zero operation-level timeouts are defined, use them
-	if operationSubOpts, ok := opts.OperationSubOptions[method+path]; ok {
-		if !reflect.DeepEqual(options.TimeoutOptions{}, operationSubOpts.Timeouts) {
-			res.Timeout = formatTimeout(operationSubOpts.Timeouts.RequestTimeout)
-		}
+	// timeouts merged from global, path and operation levels
+	if timeoutOpts := opts.GetTimeoutOpts(path, method); timeoutOpts.RequestTimeout > 0 {
+		res.Timeout = formatTimeout(timeoutOpts.RequestTimeout)
 	}
 
 	return res
 }
 
-func formatTimeout(timeout uint32) string {
-	return fmt.Sprintf("%ds", timeout)
+func formatTimeout(timeout options.Duration) string {
+	return timeout.String()
 }
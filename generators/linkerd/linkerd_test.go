@@ -3,6 +3,7 @@ package linkerd
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/require"
@@ -59,7 +60,8 @@ paths:
   /authors:
     post: {}
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -103,7 +105,8 @@ paths:
   /authors:
     post: {}
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -161,7 +164,8 @@ paths:
             type: integer
             format: int64
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -234,7 +238,8 @@ paths:
             type: integer
             format: int64
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -304,7 +309,8 @@ paths:
             type: integer
             format: int64
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -342,7 +348,7 @@ spec:
 				ClusterDomain: "cluster.local",
 			},
 			Timeouts: options.TimeoutOptions{
-				RequestTimeout: 5,
+				RequestTimeout: options.Duration(5 * time.Second),
 			},
 		},
 		spec: `openapi: 3.0.1
@@ -353,7 +359,8 @@ paths:
   /authors:
     post: {}
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -371,6 +378,42 @@ spec:
       pathRegex: /authors
     name: POST /authors
     timeout: 5s
+`,
+	},
+	{
+		name: "simple routes with sub-second timeout",
+		options: options.Options{
+			Namespace: "default",
+			Service: options.ServiceOptions{
+				Namespace: "default",
+				Name:      "webapp",
+			},
+			Cluster: options.ClusterOptions{
+				ClusterDomain: "cluster.local",
+			},
+			Timeouts: options.TimeoutOptions{
+				RequestTimeout: options.Duration(1500 * time.Millisecond),
+			},
+		},
+		spec: `openapi: 3.0.1
+paths:
+  /:
+    get: {}
+`,
+		res: `---
+apiVersion: linkerd.io/v1alpha2
+kind: ServiceProfile
+metadata:
+  creationTimestamp: null
+  name: webapp.default.svc.cluster.local
+  namespace: default
+spec:
+  routes:
+  - condition:
+      method: GET
+      pathRegex: /
+    name: GET /
+    timeout: 1500ms
 `,
 	},
 	{
@@ -385,12 +428,12 @@ spec:
 				ClusterDomain: "cluster.local",
 			},
 			Timeouts: options.TimeoutOptions{
-				RequestTimeout: 5,
+				RequestTimeout: options.Duration(5 * time.Second),
 			},
 			PathSubOptions: map[string]options.SubOptions{
 				"/authors": {
 					Timeouts: options.TimeoutOptions{
-						RequestTimeout: 6,
+						RequestTimeout: options.Duration(6 * time.Second),
 					},
 				},
 			},
@@ -409,7 +452,8 @@ paths:
         request_timeout: 6
     post: {}
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -441,12 +485,12 @@ spec:
 				ClusterDomain: "cluster.local",
 			},
 			Timeouts: options.TimeoutOptions{
-				RequestTimeout: 5,
+				RequestTimeout: options.Duration(5 * time.Second),
 			},
 			OperationSubOptions: map[string]options.SubOptions{
 				"POST/authors": {
 					Timeouts: options.TimeoutOptions{
-						RequestTimeout: 6,
+						RequestTimeout: options.Duration(6 * time.Second),
 					},
 				},
 			},
@@ -465,7 +509,8 @@ paths:
         timeouts:
           request_timeout: 6
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -542,7 +587,8 @@ paths:
         disabled: false
     post: {}
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -593,7 +639,8 @@ paths:
       x-kusk:
         disabled: false
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -638,7 +685,8 @@ paths:
     post: {}
     patch: {}
 `,
-		res: `apiVersion: linkerd.io/v1alpha2
+		res: `---
+apiVersion: linkerd.io/v1alpha2
 kind: ServiceProfile
 metadata:
   creationTimestamp: null
@@ -0,0 +1,203 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// Merger is implemented by generators able to merge the resources generated for several specs,
+// e.g. into a single resource routing to the services of all specs under one host
+type Merger interface {
+	// Merge merges the resources of the combined result in place,
+	// the merged resources are named name, or keep the name of the first one if empty
+	Merge(name string, res *Result) error
+}
+
+// Merge merges the results generated for several specs into one,
+// the results of generators not implementing Merger are only combined
+func Merge(gen Interface, name string, results []*Result) (*Result, error) {
+	res := Combine(results)
+
+	if merger, ok := gen.(Merger); ok && len(results) > 1 {
+		if err := merger.Merge(name, res); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// Combine appends the results into one, keeping their order
+func Combine(results []*Result) *Result {
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	res := &Result{}
+
+	for _, r := range results {
+		res.Objects = append(res.Objects, r.Objects...)
+		res.Warnings = append(res.Warnings, r.Warnings...)
+	}
+
+	return res
+}
+
+// MergeObjects merges the objects with the same key into the first of them using merge,
+// objects for which key returns false are left as they are.
+// The merged object takes the place of the last one of its group, so that it still follows the resources it references,
+// and is named name, followed by its number if there are several merged objects, or keeps its name if name is empty.
+// It gets the labels and annotations of all the objects of its group, except the ones they set to different values,
+// e.g. the titles of the specs, which are left out with a warning.
+func (r *Result) MergeObjects(
+	name string,
+	key func(obj *unstructured.Unstructured) (string, bool),
+	merge func(into, obj *unstructured.Unstructured) error,
+) error {
+	first := map[string]*unstructured.Unstructured{}
+	last := map[string]*unstructured.Unstructured{}
+	merged := map[string]bool{}
+	// labels and annotations left out of the merged objects, by key
+	conflicting := map[string]map[string]bool{}
+
+	var mergedKeys []string
+
+	for _, obj := range r.Objects {
+		k, ok := key(obj)
+		if !ok {
+			continue
+		}
+
+		last[k] = obj
+
+		into, ok := first[k]
+		if !ok {
+			first[k] = obj
+			continue
+		}
+
+		if err := merge(into, obj); err != nil {
+			return fmt.Errorf("unable to merge %s %s into %s: %w", obj.GetKind(), obj.GetName(), into.GetName(), err)
+		}
+
+		if !merged[k] {
+			merged[k] = true
+			mergedKeys = append(mergedKeys, k)
+			conflicting[k] = map[string]bool{}
+		}
+
+		into.SetLabels(mergeMetadata(into.GetLabels(), obj.GetLabels(), conflicting[k]))
+		into.SetAnnotations(mergeMetadata(into.GetAnnotations(), obj.GetAnnotations(), conflicting[k]))
+	}
+
+	if name != "" {
+		for i, k := range mergedKeys {
+			if i == 0 {
+				first[k].SetName(name)
+				continue
+			}
+
+			first[k].SetName(fmt.Sprintf("%s-%d", name, i+1))
+		}
+	}
+
+	for _, k := range mergedKeys {
+		if len(conflicting[k]) == 0 {
+			continue
+		}
+
+		keys := make([]string, 0, len(conflicting[k]))
+		for key := range conflicting[k] {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		r.Warn(
+			"%s %s merges resources with different values of %s, which are left out",
+			first[k].GetKind(), first[k].GetName(), strings.Join(keys, ", "),
+		)
+	}
+
+	objects := make([]*unstructured.Unstructured, 0, len(r.Objects))
+
+	for _, obj := range r.Objects {
+		k, ok := key(obj)
+		if !ok {
+			objects = append(objects, obj)
+			continue
+		}
+
+		if last[k] == obj {
+			objects = append(objects, first[k])
+		}
+	}
+
+	r.Objects = objects
+
+	return nil
+}
+
+// mergeMetadata adds the labels or annotations of from to into,
+// the ones with different values are removed from into and added to conflicting to be left out of later merges as well.
+// It returns nil rather than an empty map, so that no empty labels or annotations are set.
+func mergeMetadata(into, from map[string]string, conflicting map[string]bool) map[string]string {
+	if into == nil {
+		into = map[string]string{}
+	}
+
+	for key, value := range from {
+		if conflicting[key] {
+			continue
+		}
+
+		if existing, ok := into[key]; ok && existing != value {
+			delete(into, key)
+			conflicting[key] = true
+
+			continue
+		}
+
+		into[key] = value
+	}
+
+	for key := range conflicting {
+		delete(into, key)
+	}
+
+	if len(into) == 0 {
+		return nil
+	}
+
+	return into
+}
+
+// FromUnstructured converts the unstructured object to the typed resource using its JSON representation
+func FromUnstructured(obj *unstructured.Unstructured, into interface{}) error {
+	b, err := json.Marshal(obj.Object)
+	if err != nil {
+		return fmt.Errorf("unable to marshal resource: %+v: %w", obj.Object, err)
+	}
+
+	if err := json.Unmarshal(b, into); err != nil {
+		return fmt.Errorf("unable to convert unstructured to resource: %+v: %w", obj.Object, err)
+	}
+
+	return nil
+}
+
+// UpdateObject replaces the content of the unstructured object with the typed resource
+func UpdateObject(obj *unstructured.Unstructured, from interface{}) error {
+	u, err := ToUnstructured(from)
+	if err != nil {
+		return err
+	}
+
+	obj.Object = u.Object
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package generators
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+func TestMergeObjects(t *testing.T) {
+	newResource := func(kind, name string, hosts ...string) testResource {
+		return testResource{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "example.com/v1", Kind: kind},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       testResourceSpec{Port: 80, Hosts: hosts},
+		}
+	}
+
+	newResult := func(resources ...testResource) *Result {
+		res := &Result{}
+		for _, resource := range resources {
+			require.NoError(t, res.AddObject(resource))
+		}
+
+		return res
+	}
+
+	// merges routes by port
+	key := func(obj *unstructured.Unstructured) (string, bool) {
+		port, _, _ := unstructured.NestedInt64(obj.Object, "spec", "port")
+
+		return strconv.FormatInt(port, 10), obj.GetKind() == "Route"
+	}
+
+	merge := func(into, obj *unstructured.Unstructured) error {
+		var merged, resource testResource
+
+		if err := FromUnstructured(into, &merged); err != nil {
+			return err
+		}
+
+		if err := FromUnstructured(obj, &resource); err != nil {
+			return err
+		}
+
+		merged.Spec.Hosts = append(merged.Spec.Hosts, resource.Spec.Hosts...)
+
+		return UpdateObject(into, merged)
+	}
+
+	testCases := []struct {
+		name     string
+		mergedAs string
+		results  []*Result
+		expected []string
+	}{
+		{
+			name:     "merged into the place of the last one",
+			mergedAs: "public",
+			results: []*Result{
+				newResult(newResource("Middleware", "books-cors"), newResource("Route", "books", "books.example.org")),
+				newResult(newResource("Middleware", "authors-cors"), newResource("Route", "authors", "authors.example.org")),
+			},
+			expected: []string{"Middleware/books-cors", "Middleware/authors-cors", "Route/public"},
+		},
+		{
+			name: "first name kept",
+			results: []*Result{
+				newResult(newResource("Route", "books", "books.example.org")),
+				newResult(newResource("Route", "authors", "authors.example.org")),
+			},
+			expected: []string{"Route/books"},
+		},
+		{
+			name:     "single result left as is",
+			mergedAs: "public",
+			results: []*Result{
+				newResult(newResource("Route", "books", "books.example.org")),
+			},
+			expected: []string{"Route/books"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			res := Combine(testCase.results)
+			r.NoError(res.MergeObjects(testCase.mergedAs, key, merge))
+
+			var actual []string
+			for _, obj := range res.Objects {
+				actual = append(actual, obj.GetKind()+"/"+obj.GetName())
+			}
+
+			r.Equal(testCase.expected, actual)
+
+			hosts, _, _ := unstructured.NestedStringSlice(res.Objects[len(res.Objects)-1].Object, "spec", "hosts")
+			r.Len(hosts, len(testCase.results))
+		})
+	}
+
+	t.Run("several merged objects are numbered", func(t *testing.T) {
+		r := require.New(t)
+
+		route := newResource("Route", "books", "books.example.org")
+		otherPort := newResource("Route", "admin", "admin.example.org")
+		otherPort.Spec.Port = 8080
+
+		res := Combine([]*Result{newResult(route, otherPort), newResult(route, otherPort)})
+		r.NoError(res.MergeObjects("public", key, merge))
+
+		r.Len(res.Objects, 2)
+		r.Equal("public", res.Objects[0].GetName())
+		r.Equal("public-2", res.Objects[1].GetName())
+	})
+}
+
+func TestMergeObjectsWithMetadata(t *testing.T) {
+	r := require.New(t)
+
+	metadata := &options.MetadataOptions{
+		Labels:     map[string]string{"team": "books"},
+		NamePrefix: "team-",
+		Ownership:  true,
+	}
+
+	newResult := func(name, title string) *Result {
+		res := &Result{}
+		r.NoError(res.AddObject(testResource{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "example.com/v1", Kind: "Route"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       testResourceSpec{Port: 80, Hosts: []string{name + ".example.org"}},
+		}))
+
+		res.ApplyMetadata(metadata, &openapi3.Info{Title: title, Version: "1.0.0"})
+
+		return res
+	}
+
+	res := Combine([]*Result{newResult("books", "Books"), newResult("authors", "Authors")})
+
+	r.NoError(res.MergeObjects(
+		metadata.ResourceName("public"),
+		func(obj *unstructured.Unstructured) (string, bool) { return obj.GetKind(), true },
+		func(into, obj *unstructured.Unstructured) error { return nil },
+	))
+
+	r.Len(res.Objects, 1)
+	r.Equal("team-public", res.Objects[0].GetName())
+	r.Equal(map[string]string{
+		"team":           "books",
+		ManagedByLabel:   ManagedByValue,
+		SpecVersionLabel: "1.0.0",
+	}, res.Objects[0].GetLabels())
+	r.Equal([]string{
+		"Route team-public merges resources with different values of " + SpecTitleLabel + ", which are left out",
+	}, res.Warnings)
+}
@@ -0,0 +1,204 @@
+package generators
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+const (
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	ManagedByValue = "kusk-gen"
+
+	SpecTitleLabel   = "kusk-gen.kubeshop.io/spec-title"
+	SpecVersionLabel = "kusk-gen.kubeshop.io/spec-version"
+
+	kongPluginsAnnotation = "konghq.com/plugins"
+
+	// maxLabelValueLength is the maximum length of a Kubernetes label value
+	maxLabelValueLength = 63
+)
+
+var (
+	reInvalidLabelValueSymbols = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	reRepeatedDashes           = regexp.MustCompile(`-{2,}`)
+)
+
+// kinds whose name is meaningful to the target and must not be changed,
+// e.g. Linkerd ServiceProfile has to be named after the FQDN of the Service
+var fixedNameKinds = map[string]bool{
+	"ServiceProfile": true,
+}
+
+// ApplyMetadata stamps the common labels, annotations and name prefix and suffix onto every generated resource,
+// updating references between generated resources accordingly.
+// Objects without a kind, e.g. Envoy route configuration, are not Kubernetes resources and are left untouched.
+func (r *Result) ApplyMetadata(opts *options.MetadataOptions, info *openapi3.Info) {
+	labels := map[string]string{}
+	annotations := map[string]string{}
+
+	if opts.Ownership {
+		labels[ManagedByLabel] = ManagedByValue
+
+		// the title and the version are labels so that they could be used in selectors,
+		// e.g. to garbage-collect the resources generated for an older version of the spec
+		if info != nil {
+			if title := sanitizeLabelValue(info.Title); title != "" {
+				labels[SpecTitleLabel] = title
+			}
+
+			if version := sanitizeLabelValue(info.Version); version != "" {
+				labels[SpecVersionLabel] = version
+			}
+		}
+	}
+
+	for key, value := range opts.Labels {
+		labels[key] = value
+	}
+
+	for key, value := range opts.Annotations {
+		annotations[key] = value
+	}
+
+	// kind -> old name -> new name
+	renamed := map[string]map[string]string{}
+
+	for _, obj := range r.Objects {
+		if obj.GetKind() == "" {
+			continue
+		}
+
+		if len(labels) > 0 {
+			obj.SetLabels(mergeStringMaps(obj.GetLabels(), labels))
+		}
+
+		if len(annotations) > 0 {
+			obj.SetAnnotations(mergeStringMaps(obj.GetAnnotations(), annotations))
+		}
+
+		if (opts.NamePrefix == "" && opts.NameSuffix == "") || fixedNameKinds[obj.GetKind()] {
+			continue
+		}
+
+		name := obj.GetName()
+		newName := opts.ResourceName(name)
+
+		if renamed[obj.GetKind()] == nil {
+			renamed[obj.GetKind()] = map[string]string{}
+		}
+
+		renamed[obj.GetKind()][name] = newName
+		obj.SetName(newName)
+	}
+
+	if len(renamed) > 0 {
+		for _, obj := range r.Objects {
+			updateReferences(obj, renamed)
+		}
+	}
+}
+
+// updateReferences updates names of generated resources referenced by the object
+func updateReferences(obj *unstructured.Unstructured, renamed map[string]map[string]string) {
+	switch obj.GetKind() {
+	case "IngressRoute":
+		// Traefik routes reference Middlewares and ServersTransports
+		routes, _, _ := unstructured.NestedSlice(obj.Object, "spec", "routes")
+
+		for _, route := range routes {
+			route, ok := route.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			middlewares, _, _ := unstructured.NestedSlice(route, "middlewares")
+			for _, middleware := range middlewares {
+				if middleware, ok := middleware.(map[string]interface{}); ok {
+					renameField(middleware, "name", renamed["Middleware"])
+				}
+			}
+
+			if len(middlewares) > 0 {
+				route["middlewares"] = middlewares
+			}
+
+			services, _, _ := unstructured.NestedSlice(route, "services")
+			for _, service := range services {
+				if service, ok := service.(map[string]interface{}); ok {
+					renameField(service, "serversTransport", renamed["ServersTransport"])
+				}
+			}
+
+			if len(services) > 0 {
+				route["services"] = services
+			}
+		}
+
+		if len(routes) > 0 {
+			_ = unstructured.SetNestedSlice(obj.Object, routes, "spec", "routes")
+		}
+	case "Ingress":
+		// Kong Ingresses reference KongPlugins in an annotation
+		annotations := obj.GetAnnotations()
+
+		plugins, ok := annotations[kongPluginsAnnotation]
+		if !ok {
+			return
+		}
+
+		names := strings.Split(plugins, ",")
+		for i, name := range names {
+			name = strings.TrimSpace(name)
+			if newName, ok := renamed["KongPlugin"][name]; ok {
+				name = newName
+			}
+
+			names[i] = name
+		}
+
+		annotations[kongPluginsAnnotation] = strings.Join(names, ", ")
+		obj.SetAnnotations(annotations)
+	}
+}
+
+func renameField(obj map[string]interface{}, field string, renamed map[string]string) {
+	name, ok := obj[field].(string)
+	if !ok {
+		return
+	}
+
+	if newName, ok := renamed[name]; ok {
+		obj[field] = newName
+	}
+}
+
+// sanitizeLabelValue turns the given string into a valid label value,
+// e.g. "Swagger Petstore - OpenAPI 3.0" into "Swagger-Petstore-OpenAPI-3.0"
+func sanitizeLabelValue(value string) string {
+	value = reInvalidLabelValueSymbols.ReplaceAllString(value, "-")
+	value = reRepeatedDashes.ReplaceAllString(value, "-")
+
+	if len(value) > maxLabelValueLength {
+		value = value[:maxLabelValueLength]
+	}
+
+	// label values have to begin and end with an alphanumeric character
+	return strings.Trim(value, "-._")
+}
+
+func mergeStringMaps(dst, src map[string]string) map[string]string {
+	if dst == nil {
+		dst = map[string]string{}
+	}
+
+	for key, value := range src {
+		dst[key] = value
+	}
+
+	return dst
+}
@@ -0,0 +1,200 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+func TestApplyMetadata(t *testing.T) {
+	r := require.New(t)
+
+	var res Result
+
+	r.NoError(res.AddYAML([]byte(`
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: Middleware
+metadata:
+  name: petstore-cors
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: ServersTransport
+metadata:
+  name: petstore
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: IngressRoute
+metadata:
+  name: petstore
+  labels:
+    app: petstore
+spec:
+  routes:
+  - match: Path("/pet")
+    middlewares:
+    - name: petstore-cors
+    - name: external
+    services:
+    - name: petstore
+      serversTransport: petstore
+---
+apiVersion: configuration.konghq.com/v1
+kind: KongPlugin
+metadata:
+  name: petstore-cors
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: petstore
+  annotations:
+    konghq.com/plugins: petstore-cors, external
+---
+apiVersion: linkerd.io/v1alpha2
+kind: ServiceProfile
+metadata:
+  name: petstore.default.svc.cluster.local
+---
+name: petstore
+virtual_hosts: []
+`)))
+
+	res.ApplyMetadata(&options.MetadataOptions{
+		Labels:      map[string]string{"team": "pets"},
+		Annotations: map[string]string{"owner": "pets@example.com"},
+		NamePrefix:  "team-",
+		NameSuffix:  "-v1",
+		Ownership:   true,
+	}, &openapi3.Info{Title: "Swagger Petstore - OpenAPI 3.0", Version: "1.0.0"})
+
+	out, err := res.YAML()
+	r.NoError(err)
+	r.Equal(`---
+apiVersion: traefik.containo.us/v1alpha1
+kind: Middleware
+metadata:
+  annotations:
+    owner: pets@example.com
+  labels:
+    app.kubernetes.io/managed-by: kusk-gen
+    kusk-gen.kubeshop.io/spec-title: Swagger-Petstore-OpenAPI-3.0
+    kusk-gen.kubeshop.io/spec-version: 1.0.0
+    team: pets
+  name: team-petstore-cors-v1
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: ServersTransport
+metadata:
+  annotations:
+    owner: pets@example.com
+  labels:
+    app.kubernetes.io/managed-by: kusk-gen
+    kusk-gen.kubeshop.io/spec-title: Swagger-Petstore-OpenAPI-3.0
+    kusk-gen.kubeshop.io/spec-version: 1.0.0
+    team: pets
+  name: team-petstore-v1
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: IngressRoute
+metadata:
+  annotations:
+    owner: pets@example.com
+  labels:
+    app: petstore
+    app.kubernetes.io/managed-by: kusk-gen
+    kusk-gen.kubeshop.io/spec-title: Swagger-Petstore-OpenAPI-3.0
+    kusk-gen.kubeshop.io/spec-version: 1.0.0
+    team: pets
+  name: team-petstore-v1
+spec:
+  routes:
+  - match: Path("/pet")
+    middlewares:
+    - name: team-petstore-cors-v1
+    - name: external
+    services:
+    - name: petstore
+      serversTransport: team-petstore-v1
+---
+apiVersion: configuration.konghq.com/v1
+kind: KongPlugin
+metadata:
+  annotations:
+    owner: pets@example.com
+  labels:
+    app.kubernetes.io/managed-by: kusk-gen
+    kusk-gen.kubeshop.io/spec-title: Swagger-Petstore-OpenAPI-3.0
+    kusk-gen.kubeshop.io/spec-version: 1.0.0
+    team: pets
+  name: team-petstore-cors-v1
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    konghq.com/plugins: team-petstore-cors-v1, external
+    owner: pets@example.com
+  labels:
+    app.kubernetes.io/managed-by: kusk-gen
+    kusk-gen.kubeshop.io/spec-title: Swagger-Petstore-OpenAPI-3.0
+    kusk-gen.kubeshop.io/spec-version: 1.0.0
+    team: pets
+  name: team-petstore-v1
+---
+apiVersion: linkerd.io/v1alpha2
+kind: ServiceProfile
+metadata:
+  annotations:
+    owner: pets@example.com
+  labels:
+    app.kubernetes.io/managed-by: kusk-gen
+    kusk-gen.kubeshop.io/spec-title: Swagger-Petstore-OpenAPI-3.0
+    kusk-gen.kubeshop.io/spec-version: 1.0.0
+    team: pets
+  name: petstore.default.svc.cluster.local
+---
+name: petstore
+virtual_hosts: []
+`, out)
+}
+
+type objectsGeneratorFunc func(opts *options.Options, spec *openapi3.T) (*Result, error)
+
+func (f objectsGeneratorFunc) GenerateObjects(opts *options.Options, spec *openapi3.T) (*Result, error) {
+	return f(opts, spec)
+}
+
+func TestGenerateObjectsAppliesMetadata(t *testing.T) {
+	r := require.New(t)
+
+	gen := objectsGeneratorFunc(func(opts *options.Options, spec *openapi3.T) (*Result, error) {
+		var res Result
+
+		return &res, res.AddYAML([]byte(`
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: petstore
+`))
+	})
+
+	res, err := GenerateObjects(gen, &options.Options{
+		Metadata: options.MetadataOptions{
+			NamePrefix: "team-",
+			Ownership:  true,
+		},
+	}, &openapi3.T{Info: &openapi3.Info{Title: "Petstore", Version: "v1"}})
+	r.NoError(err)
+
+	r.Len(res.Objects, 1)
+	r.Equal("team-petstore", res.Objects[0].GetName())
+	r.Equal(map[string]string{
+		ManagedByLabel:   ManagedByValue,
+		SpecTitleLabel:   "Petstore",
+		SpecVersionLabel: "v1",
+	}, res.Objects[0].GetLabels())
+}
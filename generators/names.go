@@ -0,0 +1,50 @@
+package generators
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+var reInvalidNameSymbols = regexp.MustCompile(`[^a-z0-9]+`)
+
+// SanitizeName turns the given string into a valid Kubernetes resource name
+func SanitizeName(name string) string {
+	name = reInvalidNameSymbols.ReplaceAllString(strings.ToLower(name), "-")
+
+	return strings.Trim(name, "-")
+}
+
+// OperationResourceName returns the name of a resource generated for a single operation,
+// made of the service name and the operationId, or the method and path if the operationId is missing
+func OperationResourceName(serviceName, method, path string, operation *openapi3.Operation) string {
+	if operation.OperationID != "" {
+		return SanitizeName(serviceName + "-" + operation.OperationID)
+	}
+
+	return SanitizeName(serviceName + "-" + method + "-" + path)
+}
+
+// HostResourceName returns the name of a resource grouping the operations served on the given host,
+// the service name for the global host and the service name suffixed with the host otherwise
+func HostResourceName(opts *options.Options, host string) string {
+	if host == opts.Host {
+		return opts.Service.Name
+	}
+
+	return SanitizeName(opts.Service.Name + "-" + host)
+}
+
+// OperationLess orders operations by path, then by method.
+// Ranging over the paths of a spec visits them in a random order,
+// so generators sort the operations they collect to keep the output stable.
+func OperationLess(pathI, methodI, pathJ, methodJ string) bool {
+	if pathI != pathJ {
+		return pathI < pathJ
+	}
+
+	return methodI < methodJ
+}
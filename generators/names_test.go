@@ -0,0 +1,31 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+func TestNames(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("petstore-pet-petid", SanitizeName("/Petstore/pet/{petId}/"))
+
+	r.Equal("petstore-getpetbyid", OperationResourceName("petstore", "GET", "/pet/{petId}", &openapi3.Operation{OperationID: "getPetById"}))
+	r.Equal("petstore-get-pet-petid", OperationResourceName("petstore", "GET", "/pet/{petId}", &openapi3.Operation{}))
+
+	opts := &options.Options{
+		Host:    "example.org",
+		Service: options.ServiceOptions{Name: "petstore"},
+	}
+
+	r.Equal("petstore", HostResourceName(opts, "example.org"))
+	r.Equal("petstore-admin-example-org", HostResourceName(opts, "admin.example.org"))
+
+	r.True(OperationLess("/pet", "PUT", "/pet/{petId}", "GET"))
+	r.True(OperationLess("/pet", "POST", "/pet", "PUT"))
+	r.False(OperationLess("/pet", "PUT", "/pet", "POST"))
+}
@@ -6,11 +6,14 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kubeshop/kusk-gen/options"
 )
 
 const (
+	annotationsPrefix = "nginx.ingress.kubernetes.io/"
+
 	rewriteTargetAnnotationKey = "nginx.ingress.kubernetes.io/rewrite-target"
 
 	// CORS
@@ -92,7 +95,8 @@ func (g *Generator) generateAnnotations(
 
 	// Timeouts
 	if requestTimeout := timeoutOpts.RequestTimeout; requestTimeout > 0 {
-		strTimeout := strconv.Itoa(int(requestTimeout) / 2)
+		// ingress-nginx only accepts whole seconds, so the fraction is dropped and shorter timeouts become a second
+		strTimeout := strconv.FormatInt(int64((requestTimeout/2)/options.Duration(time.Second)), 10)
 		if strTimeout == "0" {
 			strTimeout = "1"
 		}
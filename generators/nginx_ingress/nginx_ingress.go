@@ -2,20 +2,20 @@ package nginx_ingress
 
 import (
 	"fmt"
-	"log"
-	"os"
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
-	"github.com/ghodss/yaml"
 	"github.com/spf13/pflag"
 	v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/generators/pathparams"
 	"github.com/kubeshop/kusk-gen/options"
 )
 
@@ -29,7 +29,7 @@ var (
 	pathTypePrefix   = v1.PathTypePrefix
 	pathTypeExact    = v1.PathTypeExact
 
-	openApiPathVariableRegex = regexp.MustCompile(`{[A-z]+}`)
+	openApiPathVariableRegex = regexp.MustCompile(`{[^}]+}`)
 )
 
 func init() {
@@ -81,10 +81,10 @@ func (g *Generator) Flags() *pflag.FlagSet {
 		"request per second burst",
 	)
 
-	fs.Uint32(
+	fs.Var(
+		new(options.Duration),
 		"timeouts.request_timeout",
-		0,
-		"total request timeout (seconds)",
+		"total request timeout, in seconds or as a duration, e.g. 1500ms",
 	)
 
 	fs.String(
@@ -105,13 +105,26 @@ func (g *Generator) LongDescription() string {
 }
 
 func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	res, err := generators.GenerateObjects(g, opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	res.LogWarnings()
+
+	return res.YAML()
+}
+
+func (g *Generator) GenerateObjects(opts *options.Options, spec *openapi3.T) (*generators.Result, error) {
 	if err := opts.FillDefaultsAndValidate(); err != nil {
-		return "", fmt.Errorf("failed to validate opts: %w", err)
+		return nil, fmt.Errorf("failed to validate opts: %w", err)
 	}
 
+	res := &generators.Result{}
+
 	ingresses := make([]v1.Ingress, 0)
 
-	if g.shouldSplit(opts, spec) {
+	if g.shouldSplit(res, opts, spec) {
 		for path := range spec.Paths {
 			if opts.IsPathDisabled(path) {
 				continue
@@ -133,14 +146,21 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 				&timeoutOpts,
 			)
 
-			// if path has a parameter, replace {param} with ([A-z0-9]+) and set use regex annotation to true
-			// if path has no parameter, just use path
+			// if path has a parameter, replace {param} with a capture group of the regex derived from its schema
+			// and set use regex annotation to true, if path has no parameter, just use path
 			var pathField string
 			if openApiPathVariableRegex.MatchString(path) {
-				pathField = opts.Path.Base + string(openApiPathVariableRegex.ReplaceAll([]byte(path), []byte("([A-z0-9]+)")))
+				pathField = opts.Path.Base + pathparams.Replace(path, pathparams.Find(spec.Paths[path], nil), func(_, pattern string) string {
+					return "(" + pattern + ")"
+				})
+
+				// refer to the capture groups in order. Given a path /books/{id}, will return /books/$1
+				group := 0
+				rewrite := opts.Path.Base + pathparams.Replace(path, nil, func(_, _ string) string {
+					group++
 
-				// get the first capture group of regex. Given a path /books/{id}, will return /books/
-				rewrite := opts.Path.Base + string(openApiPathVariableRegex.ReplaceAllLiteral([]byte(path), []byte("$1")))
+					return "$" + strconv.Itoa(group)
+				})
 				annotations[rewriteTargetAnnotationKey] = rewrite
 				annotations[useRegexAnnotationKey] = "true"
 			} else if path == "/" {
@@ -194,23 +214,13 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 		return ingresses[i].Name < ingresses[j].Name
 	})
 
-	return buildOutput(ingresses)
-}
-
-// Build suitable output to be piped into kubectl or a file
-func buildOutput(ingresses []v1.Ingress) (string, error) {
-	var builder strings.Builder
-
 	for _, ingress := range ingresses {
-		builder.WriteString("---\n") // indicate start of YAML resource
-		b, err := yaml.Marshal(ingress)
-		if err != nil {
-			return "", fmt.Errorf("unable to marshal ingress resource: %+v: %s", ingress, err.Error())
+		if err := res.AddObject(ingress); err != nil {
+			return nil, err
 		}
-		builder.WriteString(string(b))
 	}
 
-	return builder.String(), nil
+	return res, nil
 }
 
 // Given a path such as /books/{id} return a suitable ingress resource name
@@ -283,7 +293,7 @@ func (g *Generator) newIngressResource(
 	}
 }
 
-func (g *Generator) shouldSplit(opts *options.Options, spec *openapi3.T) bool {
+func (g *Generator) shouldSplit(res *generators.Result, opts *options.Options, spec *openapi3.T) bool {
 	if opts.Path.Split {
 		return true
 	}
@@ -297,8 +307,7 @@ func (g *Generator) shouldSplit(opts *options.Options, spec *openapi3.T) bool {
 		}
 
 		if ro.Group != "" {
-			log.New(os.Stderr, "WARN", log.Lmsgprefix).
-				Printf("ingress-nginx does not support rate limit groups. These will be ignored")
+			res.Warn("ingress-nginx does not support rate limit groups. These will be ignored")
 
 			groupUnsupportedWarned = true
 		}
@@ -312,40 +321,32 @@ func (g *Generator) shouldSplit(opts *options.Options, spec *openapi3.T) bool {
 			return true
 		}
 
-		if pathSubOptions, ok := opts.PathSubOptions[path]; ok {
-			// a path has non-zero, different from global scope CORS options
-			if !reflect.DeepEqual(options.CORSOptions{}, pathSubOptions.CORS) &&
-				!reflect.DeepEqual(opts.CORS, pathSubOptions.CORS) {
-				return true
-			}
-
-			// a path has non-zero, different from global scope rate limits options
-			if !reflect.DeepEqual(options.RateLimitOptions{}, pathSubOptions.RateLimits) &&
-				!reflect.DeepEqual(opts.RateLimits, pathSubOptions.RateLimits) {
-
-				warnGroupUnsupported(pathSubOptions.RateLimits)
+		// a path has CORS options different from global scope ones
+		if !reflect.DeepEqual(opts.CORS, opts.GetCORSOpts(path, "")) {
+			return true
+		}
 
-				if !rateLimitWarned {
-					log.New(os.Stderr, "WARN", log.Lmsgprefix).
-						Printf("Setting a rate limit option on the path level would cause a separate rate limit applied for each path")
+		// a path has rate limits options different from global scope ones
+		if rateLimitOpts := opts.GetRateLimitOpts(path, ""); !reflect.DeepEqual(opts.RateLimits, rateLimitOpts) {
+			warnGroupUnsupported(rateLimitOpts)
 
-					rateLimitWarned = true
-				}
+			if !rateLimitWarned {
+				res.Warn("Setting a rate limit option on the path level would cause a separate rate limit applied for each path")
 
-				return true
+				rateLimitWarned = true
 			}
 
-			// a path has non-zero, different from global scope timeouts options
-			if !reflect.DeepEqual(options.TimeoutOptions{}, pathSubOptions.Timeouts) &&
-				!reflect.DeepEqual(opts.Timeouts, pathSubOptions.Timeouts) {
-				return true
-			}
+			return true
+		}
+
+		// a path has timeouts options different from global scope ones
+		if !reflect.DeepEqual(opts.Timeouts, opts.GetTimeoutOpts(path, "")) {
+			return true
 		}
 
 		for method := range pathItem.Operations() {
-			if _, ok := opts.OperationSubOptions[method+path]; ok {
-				log.New(os.Stderr, "WARN", log.Lmsgprefix).
-					Printf("HTTP Method level options detected which ingress-nginx doesn't support. These will be ignored")
+			if _, ok := opts.GetOperationSubOptions(path, method); ok {
+				res.Warn("HTTP Method level options detected which ingress-nginx doesn't support. These will be ignored")
 
 				break // Only need to warn users once
 			}
@@ -366,3 +367,57 @@ func (g *Generator) generatePath(path *options.PathOptions, nginx *options.NGINX
 
 	return path.Base
 }
+
+// Merge merges the Ingresses generated for several specs with the same namespace, host and ingress-nginx annotations
+// into one Ingress with the paths of all of them, Ingresses with different annotations are kept separate
+// as the annotations apply to all the paths of an Ingress
+func (g *Generator) Merge(name string, res *generators.Result) error {
+	merge := func(into, obj *unstructured.Unstructured) error {
+		var merged, ingress v1.Ingress
+
+		if err := generators.FromUnstructured(into, &merged); err != nil {
+			return err
+		}
+
+		if err := generators.FromUnstructured(obj, &ingress); err != nil {
+			return err
+		}
+
+		mergedHTTP := merged.Spec.Rules[0].HTTP
+		mergedHTTP.Paths = append(mergedHTTP.Paths, ingress.Spec.Rules[0].HTTP.Paths...)
+
+		return generators.UpdateObject(into, merged)
+	}
+
+	return res.MergeObjects(name, mergeKey, merge)
+}
+
+// mergeKey returns the namespace, host and ingress-nginx annotations of Ingresses with a single rule
+func mergeKey(obj *unstructured.Unstructured) (string, bool) {
+	if obj.GetKind() != ingressKind {
+		return "", false
+	}
+
+	var ingress v1.Ingress
+
+	if err := generators.FromUnstructured(obj, &ingress); err != nil {
+		return "", false
+	}
+
+	if len(ingress.Spec.Rules) != 1 || ingress.Spec.Rules[0].HTTP == nil {
+		return "", false
+	}
+
+	key := []string{ingress.Namespace, ingress.Spec.Rules[0].Host}
+
+	for annotation, value := range ingress.Annotations {
+		if strings.HasPrefix(annotation, annotationsPrefix) {
+			key = append(key, annotation+"="+value)
+		}
+	}
+
+	// the host and namespace stay first
+	sort.Strings(key[2:])
+
+	return strings.Join(key, "\n"), true
+}
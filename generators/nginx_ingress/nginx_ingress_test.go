@@ -3,10 +3,12 @@ package nginx_ingress
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kubeshop/kusk-gen/generators"
 	"github.com/kubeshop/kusk-gen/options"
 	"github.com/kubeshop/kusk-gen/spec"
 )
@@ -323,6 +325,7 @@ metadata:
   annotations:
     nginx.ingress.kubernetes.io/cors-allow-headers: Content-Type
     nginx.ingress.kubernetes.io/cors-allow-methods: POST, GET, OPTIONS
+    nginx.ingress.kubernetes.io/cors-allow-origin: http://foo.example
     nginx.ingress.kubernetes.io/cors-expose-headers: X-Custom-Header
     nginx.ingress.kubernetes.io/cors-max-age: "86400"
     nginx.ingress.kubernetes.io/enable-cors: "true"
@@ -341,7 +344,7 @@ spec:
             name: webapp
             port:
               number: 7000
-        path: /bookstore/books/([A-z0-9]+)
+        path: /bookstore/books/([0-9]+)
         pathType: Exact
 status:
   loadBalancer: {}
@@ -391,7 +394,7 @@ status:
 					TrimPrefix: "/bookstore",
 				},
 				Timeouts: options.TimeoutOptions{
-					RequestTimeout: 10,
+					RequestTimeout: options.Duration(10 * time.Second),
 					IdleTimeout:    0,
 				},
 			},
@@ -733,3 +736,105 @@ status:
 		})
 	}
 }
+
+func TestMerge(t *testing.T) {
+	r := require.New(t)
+
+	newOptions := func(name, host, base, rewriteTarget string) options.Options {
+		return options.Options{
+			Namespace:    "default",
+			Host:         host,
+			Service:      options.ServiceOptions{Namespace: "default", Name: name, Port: 80},
+			Path:         options.PathOptions{Base: base},
+			NGINXIngress: options.NGINXIngressOptions{RewriteTarget: rewriteTarget},
+		}
+	}
+
+	apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(`
+openapi: 3.0.2
+info:
+  title: API
+  version: 1.0.0
+paths:
+  "/":
+    get: {}
+`))
+	r.NoError(err)
+
+	var gen Generator
+	var results []*generators.Result
+
+	for _, opts := range []options.Options{
+		newOptions("books", "api.example.org", "/books", ""),
+		newOptions("authors", "api.example.org", "/authors", ""),
+		// annotations apply to the whole Ingress, so it's kept separate
+		newOptions("legacy", "api.example.org", "/legacy", "/v1"),
+	} {
+		opts := opts
+
+		res, err := gen.GenerateObjects(&opts, apiSpec)
+		r.NoError(err)
+
+		results = append(results, res)
+	}
+
+	res, err := generators.Merge(&gen, "public-api", results)
+	r.NoError(err)
+
+	actual, err := res.YAML()
+	r.NoError(err)
+	r.Equal(`---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  creationTimestamp: null
+  name: public-api
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: api.example.org
+    http:
+      paths:
+      - backend:
+          service:
+            name: books
+            port:
+              number: 80
+        path: /books
+        pathType: Prefix
+      - backend:
+          service:
+            name: authors
+            port:
+              number: 80
+        path: /authors
+        pathType: Prefix
+status:
+  loadBalancer: {}
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/rewrite-target: /v1
+  creationTimestamp: null
+  name: legacy-ingress
+  namespace: default
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: api.example.org
+    http:
+      paths:
+      - backend:
+          service:
+            name: legacy
+            port:
+              number: 80
+        path: /legacy
+        pathType: Prefix
+status:
+  loadBalancer: {}
+`, actual)
+}
@@ -0,0 +1,183 @@
+// Package pathparams derives the regexes matching path parameters from their schemas,
+// shared by the generators building regex routes for paths with parameters
+package pathparams
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// DefaultPattern matches a path parameter without a schema telling its values, i.e. any path segment
+const DefaultPattern = "[^/]+"
+
+const uuidPattern = "[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}"
+
+var rePathParameter = regexp.MustCompile(`{([^{}/]+)}`)
+
+// Find returns the path parameters of the operation by name, including the ones of its path item it doesn't override.
+// If operation is nil, the path parameters of the path item and of all its operations are returned,
+// for routes matching the whole path rather than a single operation.
+func Find(pathItem *openapi3.PathItem, operation *openapi3.Operation) map[string]*openapi3.Parameter {
+	res := map[string]*openapi3.Parameter{}
+
+	add := func(params openapi3.Parameters, override bool) {
+		for _, param := range params {
+			if param == nil || param.Value == nil || param.Value.In != openapi3.ParameterInPath {
+				continue
+			}
+
+			if _, ok := res[param.Value.Name]; ok && !override {
+				continue
+			}
+
+			res[param.Value.Name] = param.Value
+		}
+	}
+
+	add(pathItem.Parameters, false)
+
+	if operation != nil {
+		add(operation.Parameters, true)
+
+		return res
+	}
+
+	operations := pathItem.Operations()
+
+	methods := make([]string, 0, len(operations))
+	for method := range operations {
+		methods = append(methods, method)
+	}
+
+	sort.Strings(methods)
+
+	for _, method := range methods {
+		add(operations[method].Parameters, false)
+	}
+
+	return res
+}
+
+// Pattern returns the regex matching the values of the path parameter derived from its schema:
+// the schema pattern if set, the alternation of the enum values, a UUID for the uuid format,
+// digits for integers and numbers, true or false for booleans and any path segment otherwise.
+// The pattern has no capture groups and isn't anchored.
+func Pattern(param *openapi3.Parameter) string {
+	if param == nil || param.Schema == nil || param.Schema.Value == nil {
+		return DefaultPattern
+	}
+
+	schema := param.Schema.Value
+
+	switch {
+	case schema.Pattern != "":
+		return nonCapturing(unanchored(schema.Pattern))
+	case len(schema.Enum) > 0:
+		values := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			values = append(values, regexp.QuoteMeta(fmt.Sprint(value)))
+		}
+
+		return strings.Join(values, "|")
+	case schema.Format == "uuid":
+		return uuidPattern
+	case schema.Type == "integer":
+		return "[0-9]+"
+	case schema.Type == "number":
+		return `[0-9]+(?:\.[0-9]+)?`
+	case schema.Type == "boolean":
+		return "true|false"
+	}
+
+	return DefaultPattern
+}
+
+// Replace returns the path with its parameters replaced with the regexes matching their values, see Pattern,
+// parameters missing from params match any path segment. group wraps the pattern of each parameter, e.g. into a capture group,
+// if nil the patterns with alternatives are wrapped into non-capturing groups.
+func Replace(path string, params map[string]*openapi3.Parameter, group func(name, pattern string) string) string {
+	return rePathParameter.ReplaceAllStringFunc(path, func(match string) string {
+		name := match[1 : len(match)-1]
+
+		pattern := DefaultPattern
+		if param, ok := params[name]; ok {
+			pattern = Pattern(param)
+		}
+
+		if group != nil {
+			return group(name, pattern)
+		}
+
+		if strings.Contains(pattern, "|") {
+			return "(?:" + pattern + ")"
+		}
+
+		return pattern
+	})
+}
+
+// Regex returns a regex matching the given path, with the path parameters replaced as with Replace
+// and the static parts of the path quoted, so that e.g. a dot in the base path only matches a dot
+func Regex(path string, params map[string]*openapi3.Parameter, group func(name, pattern string) string) string {
+	var res strings.Builder
+
+	last := 0
+
+	for _, loc := range rePathParameter.FindAllStringIndex(path, -1) {
+		res.WriteString(regexp.QuoteMeta(path[last:loc[0]]))
+		res.WriteString(Replace(path[loc[0]:loc[1]], params, group))
+		last = loc[1]
+	}
+
+	res.WriteString(regexp.QuoteMeta(path[last:]))
+
+	return res.String()
+}
+
+// Has tells whether the path has any parameters, e.g. /books/{id}
+func Has(path string) bool {
+	return rePathParameter.MatchString(path)
+}
+
+// unanchored drops the anchors of the whole pattern, as the parameter pattern is a part of the path pattern
+func unanchored(pattern string) string {
+	pattern = strings.TrimPrefix(pattern, "^")
+
+	if strings.HasSuffix(pattern, "$") && !strings.HasSuffix(pattern, `\$`) {
+		pattern = strings.TrimSuffix(pattern, "$")
+	}
+
+	return pattern
+}
+
+// nonCapturing turns the capture groups of the pattern into non-capturing ones,
+// so that they don't shift the groups of the path pattern used in rewrites
+func nonCapturing(pattern string) string {
+	var b strings.Builder
+
+	escaped, inClass := false, false
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		b.WriteByte(c)
+
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '(' && !inClass && (i+1 == len(pattern) || pattern[i+1] != '?'):
+			b.WriteString("?:")
+		}
+	}
+
+	return b.String()
+}
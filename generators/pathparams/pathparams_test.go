@@ -0,0 +1,118 @@
+package pathparams
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPattern(t *testing.T) {
+	testCases := []struct {
+		name     string
+		schema   *openapi3.Schema
+		expected string
+	}{
+		{
+			name:     "no schema",
+			expected: "[^/]+",
+		},
+		{
+			name:     "string",
+			schema:   openapi3.NewStringSchema(),
+			expected: "[^/]+",
+		},
+		{
+			name:     "integer",
+			schema:   openapi3.NewInt64Schema(),
+			expected: "[0-9]+",
+		},
+		{
+			name:     "number",
+			schema:   openapi3.NewFloat64Schema(),
+			expected: `[0-9]+(?:\.[0-9]+)?`,
+		},
+		{
+			name:     "boolean",
+			schema:   openapi3.NewBoolSchema(),
+			expected: "true|false",
+		},
+		{
+			name:     "uuid",
+			schema:   openapi3.NewUUIDSchema(),
+			expected: "[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}",
+		},
+		{
+			name:     "enum",
+			schema:   openapi3.NewStringSchema().WithEnum("available", "sold.out"),
+			expected: `available|sold\.out`,
+		},
+		{
+			name:     "pattern without anchors and capture groups",
+			schema:   openapi3.NewStringSchema().WithPattern(`^([a-z]+)(_[a-z0-9-]+)?[(]\($`),
+			expected: `(?:[a-z]+)(?:_[a-z0-9-]+)?[(]\(`,
+		},
+		{
+			name:     "pattern takes precedence",
+			schema:   openapi3.NewInt64Schema().WithPattern("[1-9][0-9]*"),
+			expected: "[1-9][0-9]*",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			param := openapi3.NewPathParameter("id")
+			if testCase.schema != nil {
+				param.WithSchema(testCase.schema)
+			}
+
+			require.Equal(t, testCase.expected, Pattern(param))
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	r := require.New(t)
+
+	pathItem := &openapi3.PathItem{
+		Parameters: openapi3.Parameters{
+			{Value: openapi3.NewPathParameter("userId").WithSchema(openapi3.NewUUIDSchema())},
+			{Value: openapi3.NewPathParameter("status").WithSchema(openapi3.NewStringSchema())},
+		},
+		Get: &openapi3.Operation{
+			Parameters: openapi3.Parameters{
+				// overrides the path item parameter
+				{Value: openapi3.NewPathParameter("status").WithSchema(openapi3.NewStringSchema().WithEnum("active", "blocked"))},
+				{Value: openapi3.NewQueryParameter("limit").WithSchema(openapi3.NewInt64Schema())},
+			},
+		},
+		Delete: &openapi3.Operation{
+			Parameters: openapi3.Parameters{
+				{Value: openapi3.NewPathParameter("orderId").WithSchema(openapi3.NewInt64Schema())},
+			},
+		},
+	}
+
+	path := "/users/{userId}/orders/{status}/{orderId}"
+	uuid := Pattern(pathItem.Parameters[0].Value)
+
+	params := Find(pathItem, pathItem.Get)
+	r.Len(params, 2)
+	r.Equal("/users/"+uuid+"/orders/(?:active|blocked)/[^/]+", Replace(path, params, nil))
+
+	// the parameters of all the operations of the path item
+	params = Find(pathItem, nil)
+	r.Len(params, 3)
+	r.Equal("/users/"+uuid+"/orders/[^/]+/[0-9]+", Replace(path, params, nil))
+
+	captured := Replace(path, params, func(name, pattern string) string {
+		return "(" + pattern + ")"
+	})
+	r.Equal("/users/("+uuid+")/orders/([^/]+)/([0-9]+)", captured)
+
+	r.Equal(`/api\.v1/users/`+uuid+`/orders/[^/]+/[0-9]+`, Regex("/api.v1"+path, params, nil))
+
+	r.True(Has(path))
+	r.False(Has(strings.Split(path, "{")[0]))
+}
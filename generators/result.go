@@ -0,0 +1,121 @@
+package generators
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Result is the structured output of a generator
+type Result struct {
+	// Objects are the generated resources in the order they are written to the output
+	Objects []*unstructured.Unstructured
+
+	// Warnings are non-fatal issues found during generation,
+	// e.g. options that are not supported by the target and were ignored
+	Warnings []string
+}
+
+// AddObject converts the typed resource to an unstructured object and appends it to the result
+func (r *Result) AddObject(obj interface{}) error {
+	u, err := ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	r.Objects = append(r.Objects, u)
+
+	return nil
+}
+
+// AddYAML decodes a multi-document YAML stream, e.g. rendered from a template,
+// and appends the resulting objects to the result. Empty documents are skipped.
+func (r *Result) AddYAML(data []byte) error {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("unable to read YAML document: %w", err)
+		}
+
+		b, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return fmt.Errorf("unable to decode YAML document: %s: %w", doc, err)
+		}
+
+		var obj map[string]interface{}
+
+		if err := json.Unmarshal(b, &obj); err != nil {
+			return fmt.Errorf("unable to decode YAML document: %s: %w", doc, err)
+		}
+
+		if len(obj) == 0 {
+			continue
+		}
+
+		r.Objects = append(r.Objects, &unstructured.Unstructured{Object: obj})
+	}
+}
+
+// Warn appends a formatted warning to the result
+func (r *Result) Warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// LogWarnings prints the result warnings to stderr
+func (r *Result) LogWarnings() {
+	logger := log.New(os.Stderr, "[WARN]: ", log.Lmsgprefix)
+
+	for _, warning := range r.Warnings {
+		logger.Println(warning)
+	}
+}
+
+// YAML builds suitable output to be piped into kubectl or a file
+func (r *Result) YAML() (string, error) {
+	var builder strings.Builder
+
+	for _, obj := range r.Objects {
+		builder.WriteString("---\n") // indicate start of YAML resource
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return "", fmt.Errorf("unable to marshal %s resource: %+v: %s", obj.GetKind(), obj.Object, err.Error())
+		}
+		builder.WriteString(string(b))
+	}
+
+	return builder.String(), nil
+}
+
+// ToUnstructured converts the typed resource to an unstructured object
+// using its JSON representation, so that the output of both is the same
+func ToUnstructured(obj interface{}) (*unstructured.Unstructured, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal resource: %+v: %w", obj, err)
+	}
+
+	var res map[string]interface{}
+
+	// k8s.io/apimachinery json keeps integers as int64 rather than float64
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("unable to convert resource to unstructured: %+v: %w", obj, err)
+	}
+
+	return &unstructured.Unstructured{Object: res}, nil
+}
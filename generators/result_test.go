@@ -0,0 +1,77 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type testResource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec testResourceSpec `json:"spec"`
+}
+
+type testResourceSpec struct {
+	Port  int32    `json:"port"`
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+func TestResult(t *testing.T) {
+	r := require.New(t)
+
+	var res Result
+
+	r.NoError(res.AddObject(testResource{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "example.com/v1", Kind: "Example"},
+		ObjectMeta: metav1.ObjectMeta{Name: "typed", Namespace: "default"},
+		Spec:       testResourceSpec{Port: 80, Hosts: []string{"example.com"}},
+	}))
+
+	r.NoError(res.AddYAML([]byte(`
+---
+apiVersion: example.com/v1
+kind: Example
+metadata:
+  name: rendered
+spec:
+  port: 8080
+---
+`)))
+
+	res.Warn("option %s is not supported", "foo")
+
+	r.Len(res.Objects, 2)
+	r.Equal([]string{"option foo is not supported"}, res.Warnings)
+
+	r.Equal("Example", res.Objects[0].GetKind())
+	r.Equal("typed", res.Objects[0].GetName())
+	r.Equal(int64(80), res.Objects[0].Object["spec"].(map[string]interface{})["port"])
+
+	r.Equal("rendered", res.Objects[1].GetName())
+	r.Equal(int64(8080), res.Objects[1].Object["spec"].(map[string]interface{})["port"])
+
+	out, err := res.YAML()
+	r.NoError(err)
+	r.Equal(`---
+apiVersion: example.com/v1
+kind: Example
+metadata:
+  creationTimestamp: null
+  name: typed
+  namespace: default
+spec:
+  hosts:
+  - example.com
+  port: 80
+---
+apiVersion: example.com/v1
+kind: Example
+metadata:
+  name: rendered
+spec:
+  port: 8080
+`, out)
+}
@@ -5,18 +5,21 @@ import (
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/spf13/pflag"
 
-	"github.com/ghodss/yaml"
 	traefikDynamicConfig "github.com/traefik/traefik/v2/pkg/config/dynamic"
 	traefikCRD "github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/generators/pathparams"
 	"github.com/kubeshop/kusk-gen/options"
 )
 
@@ -56,6 +59,12 @@ func (g *Generator) Flags() *pflag.FlagSet {
 		"a prefix to trim from the URL before forwarding to the upstream Service",
 	)
 
+	fs.Bool(
+		"path.prefix",
+		false,
+		"match the paths of the operations as prefixes rather than exactly",
+	)
+
 	fs.Uint32(
 		"rate_limits.rps",
 		0,
@@ -68,16 +77,16 @@ func (g *Generator) Flags() *pflag.FlagSet {
 		"request per second burst",
 	)
 
-	fs.Uint32(
+	fs.Var(
+		new(options.Duration),
 		"timeouts.request_timeout",
-		0,
-		"total request timeout (seconds)",
+		"total request timeout, in seconds or as a duration, e.g. 1500ms",
 	)
 
-	fs.Uint32(
+	fs.Var(
+		new(options.Duration),
 		"timeouts.idle_timeout",
-		0,
-		"idle connection timeout (seconds)",
+		"idle connection timeout, in seconds or as a duration, e.g. 2m",
 	)
 
 	fs.String(
@@ -98,10 +107,20 @@ func (g *Generator) LongDescription() string {
 }
 
 func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, error) {
+	res, err := generators.GenerateObjects(g, opts, spec)
+	if err != nil {
+		return "", err
+	}
+
+	res.LogWarnings()
+
+	return res.YAML()
+}
+
+func (g *Generator) GenerateObjects(opts *options.Options, spec *openapi3.T) (*generators.Result, error) {
 	if err := opts.FillDefaultsAndValidate(); err != nil {
-		return "", fmt.Errorf("failed to validate opts: %w", err)
+		return nil, fmt.Errorf("failed to validate opts: %w", err)
 	}
-	host := opts.Host
 	base := opts.Path.Base
 	// K8s serviceName for created resources are based on service serviceName
 	serviceName := opts.Service.Name
@@ -151,24 +170,21 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 		// x-kusk options per path
 		pathSubOpts, ok := opts.PathSubOptions[path]
 		if ok {
-			if pathSubOpts.Host != "" && pathSubOpts.Host != host {
-				host = pathSubOpts.Host
-			}
-			// if non-zero path-level CORS options are different, override with them
+			// if path-level CORS options are set, override with them merged over the global ones
 			if !reflect.DeepEqual(options.CORSOptions{}, pathSubOpts.CORS) {
-				corsMiddleware := generateCORSMiddleware(generateResourceName([]string{serviceName, path, "cors"}), namespace, pathSubOpts.CORS)
+				corsMiddleware := generateCORSMiddleware(generateResourceName([]string{serviceName, path, "cors"}), namespace, opts.GetCORSOpts(path, ""))
 				pathMiddlewares["cors"] = corsMiddleware
 				allMiddlewares = append(allMiddlewares, corsMiddleware)
 			}
 
 			if !reflect.DeepEqual(options.RateLimitOptions{}, pathSubOpts.RateLimits) {
-				rateLimitMiddleware := generateRateLimitMiddleware(generateResourceName([]string{serviceName, path, "ratelimit"}), namespace, pathSubOpts.RateLimits)
+				rateLimitMiddleware := generateRateLimitMiddleware(generateResourceName([]string{serviceName, path, "ratelimit"}), namespace, opts.GetRateLimitOpts(path, ""))
 				pathMiddlewares["ratelimit"] = rateLimitMiddleware
 				allMiddlewares = append(allMiddlewares, rateLimitMiddleware)
 			}
 
 			if !reflect.DeepEqual(options.TimeoutOptions{}, pathSubOpts.Timeouts) {
-				pathServiceServersTransport = generateServerTransport(generateResourceName([]string{serviceName, path}), opts.Namespace, pathSubOpts.Timeouts)
+				pathServiceServersTransport = generateServerTransport(generateResourceName([]string{serviceName, path}), opts.Namespace, opts.GetTimeoutOpts(path, ""))
 				allServersTransports = append(allServersTransports, pathServiceServersTransport)
 			}
 		}
@@ -183,36 +199,30 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 			// Create copy of path middlewares map to further override per method
 			opMiddlewares := copyMiddlewareMap(pathMiddlewares)
 
-			// We override any suboptions
-			opSubOpts, ok := opts.OperationSubOptions[method+path]
+			// We override any suboptions, set on the operation or its tags
+			opSubOpts, ok := opts.GetOperationSubOptions(path, method)
 			opServiceServersTransport := pathServiceServersTransport
 			if ok {
-				if opts.IsOperationDisabled(path, method) {
-					continue
-				}
-				if opSubOpts.Host != "" && opSubOpts.Host != host {
-					host = opSubOpts.Host
-				}
-				// if non-zero operation level CORS options are different, override with them
+				// if operation level CORS options are set, override with them merged over the path and global ones
 				if !reflect.DeepEqual(options.CORSOptions{}, opSubOpts.CORS) {
-					corsMiddleware := generateCORSMiddleware(generateResourceName([]string{serviceName, path, method, "cors"}), namespace, opSubOpts.CORS)
+					corsMiddleware := generateCORSMiddleware(generateResourceName([]string{serviceName, path, method, "cors"}), namespace, opts.GetCORSOpts(path, method))
 					opMiddlewares["cors"] = corsMiddleware
 					allMiddlewares = append(allMiddlewares, corsMiddleware)
 				}
 
 				if !reflect.DeepEqual(options.RateLimitOptions{}, opSubOpts.RateLimits) {
-					rateLimitMiddleware := generateRateLimitMiddleware(generateResourceName([]string{serviceName, path, "ratelimit"}), namespace, opSubOpts.RateLimits)
+					rateLimitMiddleware := generateRateLimitMiddleware(generateResourceName([]string{serviceName, path, method, "ratelimit"}), namespace, opts.GetRateLimitOpts(path, method))
 					opMiddlewares["ratelimit"] = rateLimitMiddleware
 					allMiddlewares = append(allMiddlewares, rateLimitMiddleware)
 				}
 
 				if !reflect.DeepEqual(options.TimeoutOptions{}, opSubOpts.Timeouts) {
-					opServiceServersTransport = generateServerTransport(generateResourceName([]string{serviceName, path, method}), namespace, opSubOpts.Timeouts)
+					opServiceServersTransport = generateServerTransport(generateResourceName([]string{serviceName, path, method}), namespace, opts.GetTimeoutOpts(path, method))
 					allServersTransports = append(allServersTransports, opServiceServersTransport)
 				}
 			}
 
-			matchRule := generateMatchRule(host, base, path, method)
+			matchRule := generateMatchRule(opts.GetHost(path, method), base, path, method, pathparams.Find(pathItem, pathItem.GetOperation(method)), opts.Path.Prefix)
 			service := traefikCRD.Service{
 				LoadBalancerSpec: traefikCRD.LoadBalancerSpec{
 					Name:             serviceName,
@@ -232,7 +242,7 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 	}
 
 	if len(routes) == 0 {
-		return "", nil
+		return &generators.Result{}, nil
 	}
 
 	// Finally generate Ingress spec and object itself
@@ -250,7 +260,7 @@ func (g *Generator) Generate(opts *options.Options, spec *openapi3.T) (string, e
 		TypeMeta:   metav1.TypeMeta{Kind: "IngressRoute", APIVersion: APIVersion},
 		ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: namespace},
 	}
-	return buildOutput(ingressRoute, allMiddlewares, allServersTransports)
+	return buildResult(ingressRoute, allMiddlewares, allServersTransports)
 }
 
 func generateCORSMiddleware(name string, namespace string, corsOpts options.CORSOptions) traefikCRD.Middleware {
@@ -305,14 +315,23 @@ func generateServerTransport(name string, namespace string, timeouts options.Tim
 		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
 		Spec: traefikCRD.ServersTransportSpec{
 			ForwardingTimeouts: &traefikCRD.ForwardingTimeouts{
-				IdleConnTimeout:       &intstr.IntOrString{IntVal: int32(timeouts.IdleTimeout)},
-				ResponseHeaderTimeout: &intstr.IntOrString{IntVal: int32(timeouts.RequestTimeout)},
-				DialTimeout:           &intstr.IntOrString{IntVal: int32(timeouts.RequestTimeout)},
+				IdleConnTimeout:       durationIntOrString(timeouts.IdleTimeout),
+				ResponseHeaderTimeout: durationIntOrString(timeouts.RequestTimeout),
+				DialTimeout:           durationIntOrString(timeouts.RequestTimeout),
 			},
 		},
 	}
 }
 
+// durationIntOrString returns whole seconds as an integer and shorter durations as a string, e.g. 250ms
+func durationIntOrString(d options.Duration) *intstr.IntOrString {
+	if d%options.Duration(time.Second) == 0 {
+		return &intstr.IntOrString{IntVal: int32(d.Seconds())}
+	}
+
+	return &intstr.IntOrString{Type: intstr.String, StrVal: d.String()}
+}
+
 func generateMiddlewaresRefs(middlewares []traefikCRD.Middleware) []traefikCRD.MiddlewareRef {
 	middlewaresRefs := []traefikCRD.MiddlewareRef{}
 	for _, m := range middlewares {
@@ -321,19 +340,30 @@ func generateMiddlewaresRefs(middlewares []traefikCRD.Middleware) []traefikCRD.M
 	return middlewaresRefs
 }
 
-func generateMatchRule(host string, base string, path string, method string) string {
+func generateMatchRule(host string, base string, path string, method string, params map[string]*openapi3.Parameter, prefix bool) string {
 	const httpPathSeparator string = "/"
 	// Avoids path joins (removes // in e.g. /path//subpath, or //subpath)
 	fullPath := fmt.Sprintf(`%s/%s`, strings.TrimSuffix(base, httpPathSeparator), strings.TrimPrefix(path, httpPathSeparator))
-	// Create rules to filter request on
+	// Traefik matches path parameters given as {name:regexp}
+	fullPath = pathparams.Replace(fullPath, params, func(name, pattern string) string {
+		return "{" + name + ":" + pattern + "}"
+	})
+	// Paths are matched exactly unless prefix matching is configured,
+	// otherwise e.g. /pets would also take the requests of /pets/{petId}
+	pathMatcher := "Path"
+	if prefix {
+		pathMatcher = "PathPrefix"
+	}
+	// Create rules to filter request on, values are quoted as Go strings
+	// so that the backslashes of path parameter regexes are escaped
 	rules := []string{}
 	// Host filter
 	if host != "" {
-		rules = append(rules, fmt.Sprintf("Host(\"%s\")", host))
+		rules = append(rules, fmt.Sprintf("Host(%s)", strconv.Quote(host)))
 	}
-	rules = append(rules, fmt.Sprintf("PathPrefix(\"%s\")", fullPath))
-	rules = append(rules, fmt.Sprintf("Method(\"%s\")", method))
-	// returns e.g. Host(`example.org`) && PathPrefix(`/petstore/api/v3/pet`) && Method(`POST`)
+	rules = append(rules, fmt.Sprintf("%s(%s)", pathMatcher, strconv.Quote(fullPath)))
+	rules = append(rules, fmt.Sprintf("Method(%s)", strconv.Quote(method)))
+	// returns e.g. Host("example.org") && Path("/petstore/api/v3/pet/{petId:[0-9]+}") && Method("POST")
 	return strings.Join(rules, " && ")
 }
 
@@ -345,40 +375,29 @@ func generateResourceName(s []string) string {
 	return strings.ToLower(strings.Join(s, "-"))
 }
 
-// Build suitable output to be piped into kubectl or a file
-func buildOutput(ingressRoute traefikCRD.IngressRoute, middlewares []traefikCRD.Middleware, serversTransports []traefikCRD.ServersTransport) (string, error) {
-	var builder strings.Builder
-	// Middlewares first
-	builder.WriteString("\n") // initial line feed
+// buildResult collects the resources, middlewares and servers transports first
+// as they are referenced by the IngressRoute
+func buildResult(ingressRoute traefikCRD.IngressRoute, middlewares []traefikCRD.Middleware, serversTransports []traefikCRD.ServersTransport) (*generators.Result, error) {
+	res := &generators.Result{}
 
 	// Sort the list for tests to be stable
 	sort.SliceStable(middlewares, func(i, j int) bool {
 		return middlewares[i].ObjectMeta.Name < middlewares[j].ObjectMeta.Name
 	})
 	for _, middleware := range middlewares {
-		builder.WriteString("---\n") // indicate start of YAML resource
-		b, err := yaml.Marshal(middleware)
-		if err != nil {
-			return "", fmt.Errorf("unable to marshal Middleware resource: %+v: %s", middleware, err.Error())
+		if err := res.AddObject(middleware); err != nil {
+			return nil, err
 		}
-		builder.WriteString(string(b))
 	}
 	for _, serversTransport := range serversTransports {
-		builder.WriteString("---\n") // indicate start of YAML resource
-		b, err := yaml.Marshal(serversTransport)
-		if err != nil {
-			return "", fmt.Errorf("unable to marshal ServersTransport resource: %+v: %s", serversTransport, err.Error())
+		if err := res.AddObject(serversTransport); err != nil {
+			return nil, err
 		}
-		builder.WriteString(string(b))
 	}
-	// IngressRoute
-	builder.WriteString("---\n") // indicate start of YAML resource
-	b, err := yaml.Marshal(ingressRoute)
-	if err != nil {
-		return "", fmt.Errorf("unable to marshal IngressRoute resource: %+v: %s", ingressRoute, err.Error())
+	if err := res.AddObject(ingressRoute); err != nil {
+		return nil, err
 	}
-	builder.WriteString(string(b))
-	return builder.String(), nil
+	return res, nil
 }
 
 func middlewareMapToList(m map[string]traefikCRD.Middleware) []traefikCRD.Middleware {
@@ -400,3 +419,29 @@ func copyMiddlewareMap(m map[string]traefikCRD.Middleware) map[string]traefikCRD
 	}
 	return resMap
 }
+
+// Merge merges the IngressRoutes generated for several specs in the same namespace into one,
+// the Middlewares and ServersTransports they reference are named after their services and are kept as they are
+func (g *Generator) Merge(name string, res *generators.Result) error {
+	key := func(obj *unstructured.Unstructured) (string, bool) {
+		return obj.GetNamespace(), obj.GetKind() == "IngressRoute"
+	}
+
+	merge := func(into, obj *unstructured.Unstructured) error {
+		var merged, ingressRoute traefikCRD.IngressRoute
+
+		if err := generators.FromUnstructured(into, &merged); err != nil {
+			return err
+		}
+
+		if err := generators.FromUnstructured(obj, &ingressRoute); err != nil {
+			return err
+		}
+
+		merged.Spec.Routes = append(merged.Spec.Routes, ingressRoute.Spec.Routes...)
+
+		return generators.UpdateObject(into, merged)
+	}
+
+	return res.MergeObjects(name, key, merge)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/knadh/koanf/providers/structs"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kubeshop/kusk-gen/generators"
 	"github.com/kubeshop/kusk-gen/options"
 	"github.com/kubeshop/kusk-gen/spec"
 )
@@ -40,8 +41,7 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
@@ -65,7 +65,7 @@ spec:
   - web
   routes:
   - kind: Rule
-    match: PathPrefix("/pet") && Method("PUT")
+    match: Path("/pet") && Method("PUT")
     services:
     - name: petstore
       namespace: nondefault
@@ -96,8 +96,7 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
@@ -121,7 +120,7 @@ spec:
   - web
   routes:
   - kind: Rule
-    match: PathPrefix("/somepath/pet") && Method("PUT")
+    match: Path("/somepath/pet") && Method("PUT")
     services:
     - name: petstore
       namespace: nondefault
@@ -153,8 +152,7 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: traefik.containo.us/v1alpha1
 kind: Middleware
 metadata:
@@ -189,7 +187,7 @@ spec:
   - web
   routes:
   - kind: Rule
-    match: PathPrefix("/somepath/pet") && Method("PUT")
+    match: Path("/somepath/pet") && Method("PUT")
     middlewares:
     - name: petstore-strip-prefix
       namespace: nondefault
@@ -222,8 +220,7 @@ paths:
         '200':
           description: Successful operation
 `,
-			res: `
----
+			res: `---
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
@@ -247,7 +244,7 @@ spec:
   - web
   routes:
   - kind: Rule
-    match: Host("example.com") && PathPrefix("/pet") && Method("PUT")
+    match: Host("example.com") && Path("/pet") && Method("PUT")
     services:
     - name: petstore
       namespace: nondefault
@@ -344,8 +341,7 @@ paths:
            rps: 40
            burst: 80
 `,
-			res: `
----
+			res: `---
 apiVersion: traefik.containo.us/v1alpha1
 kind: Middleware
 metadata:
@@ -424,7 +420,7 @@ apiVersion: traefik.containo.us/v1alpha1
 kind: Middleware
 metadata:
   creationTimestamp: null
-  name: petstore-petfindbystatus-ratelimit
+  name: petstore-petfindbystatus-get-ratelimit
   namespace: nondefault
 spec:
   rateLimit:
@@ -478,7 +474,7 @@ spec:
   - web
   routes:
   - kind: Rule
-    match: PathPrefix("/pet") && Method("POST")
+    match: Path("/pet") && Method("POST")
     middlewares:
     - name: petstore-pet-post-cors
       namespace: nondefault
@@ -488,17 +484,150 @@ spec:
       port: 7777
       serversTransport: petstore-pet-post
   - kind: Rule
-    match: PathPrefix("/pet/findByStatus") && Method("GET")
+    match: Path("/pet/findByStatus") && Method("GET")
     middlewares:
     - name: petstore-petfindbystatus-get-cors
       namespace: nondefault
-    - name: petstore-petfindbystatus-ratelimit
+    - name: petstore-petfindbystatus-get-ratelimit
       namespace: nondefault
     services:
     - name: petstore
       namespace: nondefault
       port: 7777
       serversTransport: petstore
+`,
+		},
+		{
+			name: "path parameters matched with regexes derived from their schemas",
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+x-kusk:
+  namespace: nondefault
+  service:
+    name: petstore
+    namespace: nondefault
+    port: 7000
+paths:
+  "/pet/{petId}/{status}":
+    parameters:
+    - name: petId
+      in: path
+      required: true
+      schema:
+        type: integer
+    get:
+      parameters:
+      - name: status
+        in: path
+        required: true
+        schema:
+          type: string
+          enum:
+          - available
+          - sold
+      responses:
+        '200':
+          description: Successful operation
+`,
+			res: `---
+apiVersion: traefik.containo.us/v1alpha1
+kind: ServersTransport
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: nondefault
+spec:
+  forwardingTimeouts:
+    dialTimeout: 0
+    idleConnTimeout: 0
+    responseHeaderTimeout: 0
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: IngressRoute
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: nondefault
+spec:
+  entryPoints:
+  - web
+  routes:
+  - kind: Rule
+    match: Path("/pet/{petId:[0-9]+}/{status:available|sold}") && Method("GET")
+    services:
+    - name: petstore
+      namespace: nondefault
+      port: 7000
+      serversTransport: petstore
+`,
+		},
+		{
+			name: "prefix matching configured",
+			spec: `
+openapi: 3.0.2
+info:
+  title: Swagger Petstore - OpenAPI 3.0
+  version: 1.0.5
+x-kusk:
+  namespace: nondefault
+  path:
+    prefix: true
+  service:
+    name: petstore
+    namespace: nondefault
+    port: 7000
+paths:
+  "/pets":
+    get: {}
+  "/pets/{weight}":
+    get:
+      parameters:
+      - name: weight
+        in: path
+        required: true
+        schema:
+          type: number
+`,
+			res: `---
+apiVersion: traefik.containo.us/v1alpha1
+kind: ServersTransport
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: nondefault
+spec:
+  forwardingTimeouts:
+    dialTimeout: 0
+    idleConnTimeout: 0
+    responseHeaderTimeout: 0
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: IngressRoute
+metadata:
+  creationTimestamp: null
+  name: petstore
+  namespace: nondefault
+spec:
+  entryPoints:
+  - web
+  routes:
+  - kind: Rule
+    match: PathPrefix("/pets") && Method("GET")
+    services:
+    - name: petstore
+      namespace: nondefault
+      port: 7000
+      serversTransport: petstore
+  - kind: Rule
+    match: PathPrefix("/pets/{weight:[0-9]+(?:\\.[0-9]+)?}") && Method("GET")
+    services:
+    - name: petstore
+      namespace: nondefault
+      port: 7000
+      serversTransport: petstore
 `,
 		},
 		{
@@ -544,8 +673,7 @@ paths:
         disabled: false
     post: {}
 `,
-			res: `
----
+			res: `---
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
@@ -569,7 +697,7 @@ spec:
   - web
   routes:
   - kind: Rule
-    match: PathPrefix("/") && Method("GET")
+    match: Path("/") && Method("GET")
     services:
     - name: petstore
       namespace: nondefault
@@ -602,8 +730,7 @@ paths:
       x-kusk:
         disabled: false
 `,
-			res: `
----
+			res: `---
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
@@ -627,14 +754,14 @@ spec:
   - web
   routes:
   - kind: Rule
-    match: PathPrefix("/") && Method("PATCH")
+    match: Path("/") && Method("PATCH")
     services:
     - name: petstore
       namespace: nondefault
       port: 7777
       serversTransport: petstore
   - kind: Rule
-    match: PathPrefix("/") && Method("POST")
+    match: Path("/") && Method("POST")
     services:
     - name: petstore
       namespace: nondefault
@@ -663,8 +790,7 @@ paths:
     post: {}
     patch: {}
 `,
-			res: `
----
+			res: `---
 apiVersion: traefik.containo.us/v1alpha1
 kind: ServersTransport
 metadata:
@@ -688,14 +814,14 @@ spec:
   - web
   routes:
   - kind: Rule
-    match: PathPrefix("/") && Method("PATCH")
+    match: Path("/") && Method("PATCH")
     services:
     - name: petstore
       namespace: nondefault
       port: 7777
       serversTransport: petstore
   - kind: Rule
-    match: PathPrefix("/") && Method("POST")
+    match: Path("/") && Method("POST")
     services:
     - name: petstore
       namespace: nondefault
@@ -731,3 +857,127 @@ spec:
 		})
 	}
 }
+
+func TestMerge(t *testing.T) {
+	r := require.New(t)
+
+	specs := []string{`
+openapi: 3.0.2
+info:
+  title: Books
+  version: 1.0.0
+x-kusk:
+  host: api.example.org
+  path:
+    base: /books
+  service:
+    name: books
+    namespace: default
+paths:
+  "/":
+    get: {}
+`, `
+openapi: 3.0.2
+info:
+  title: Authors
+  version: 1.0.0
+x-kusk:
+  host: api.example.org
+  path:
+    base: /authors
+  service:
+    name: authors
+    namespace: default
+  rate_limits:
+    rps: 10
+paths:
+  "/":
+    get: {}
+`}
+
+	var gen Generator
+	var results []*generators.Result
+
+	for _, s := range specs {
+		apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(s))
+		r.NoError(err, "failed to parse spec")
+
+		opts, err := spec.GetOptions(apiSpec)
+		r.NoError(err, "failed to get options")
+
+		res, err := gen.GenerateObjects(opts, apiSpec)
+		r.NoError(err)
+
+		results = append(results, res)
+	}
+
+	res, err := generators.Merge(&gen, "public-api", results)
+	r.NoError(err)
+
+	actual, err := res.YAML()
+	r.NoError(err)
+	r.Equal(`---
+apiVersion: traefik.containo.us/v1alpha1
+kind: ServersTransport
+metadata:
+  creationTimestamp: null
+  name: books
+  namespace: default
+spec:
+  forwardingTimeouts:
+    dialTimeout: 0
+    idleConnTimeout: 0
+    responseHeaderTimeout: 0
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: Middleware
+metadata:
+  creationTimestamp: null
+  name: authors-ratelimit
+  namespace: default
+spec:
+  rateLimit:
+    average: 10
+    burst: 0
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: ServersTransport
+metadata:
+  creationTimestamp: null
+  name: authors
+  namespace: default
+spec:
+  forwardingTimeouts:
+    dialTimeout: 0
+    idleConnTimeout: 0
+    responseHeaderTimeout: 0
+---
+apiVersion: traefik.containo.us/v1alpha1
+kind: IngressRoute
+metadata:
+  creationTimestamp: null
+  name: public-api
+  namespace: default
+spec:
+  entryPoints:
+  - web
+  routes:
+  - kind: Rule
+    match: Host("api.example.org") && Path("/books/") && Method("GET")
+    services:
+    - name: books
+      namespace: default
+      port: 80
+      serversTransport: books
+  - kind: Rule
+    match: Host("api.example.org") && Path("/authors/") && Method("GET")
+    middlewares:
+    - name: authors-ratelimit
+      namespace: default
+    services:
+    - name: authors
+      namespace: default
+      port: 80
+      serversTransport: authors
+`, actual)
+}
@@ -1,6 +1,16 @@
 package options
 
-import "reflect"
+import (
+	"errors"
+	"net/url"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// wildcardOrigin allows requests from any origin
+const wildcardOrigin = "*"
+
+var httpMethods = []interface{}{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "CONNECT", "OPTIONS", "TRACE"}
 
 type CORSOptions struct {
 	Origins       []string `yaml:"origins,omitempty" json:"origins,omitempty"`
@@ -14,29 +24,102 @@ type CORSOptions struct {
 	MaxAge      int   `yaml:"max_age,omitempty" json:"max_age,omitempty"`
 }
 
+// GetCORSOpts returns CORS options for the operation, merging global, tag, path and operation level options field by field.
+// Use an empty method to get path level options.
 func (o *Options) GetCORSOpts(path, method string) CORSOptions {
 	// take global CORS options
 	corsOpts := o.CORS
 
-	// if non-zero path-level CORS options are different, override with them
+	// override with the fields set on the operation's tags
+	for _, tagSubOpts := range o.operationTagSubOptions(path, method) {
+		corsOpts = corsOpts.merge(tagSubOpts.CORS)
+	}
+
+	// override with the fields set on the path level
 	if pathSubOpts, ok := o.PathSubOptions[path]; ok {
-		if !reflect.DeepEqual(CORSOptions{}, pathSubOpts.CORS) &&
-			!reflect.DeepEqual(corsOpts, pathSubOpts.CORS) {
-			corsOpts = pathSubOpts.CORS
-		}
+		corsOpts = corsOpts.merge(pathSubOpts.CORS)
 	}
 
-	// if non-zero operation-level CORS options are different, override them
-	if opSubOpts, ok := o.OperationSubOptions[path]; ok {
-		if !reflect.DeepEqual(CORSOptions{}, opSubOpts.CORS) &&
-			!reflect.DeepEqual(corsOpts, opSubOpts.CORS) {
-			corsOpts = opSubOpts.CORS
-		}
+	// override with the fields set on the operation level
+	if opSubOpts, ok := o.OperationSubOptions[method+path]; ok {
+		corsOpts = corsOpts.merge(opSubOpts.CORS)
 	}
 
 	return corsOpts
 }
 
+// merge returns the options with the fields set in override replaced
+func (o CORSOptions) merge(override CORSOptions) CORSOptions {
+	if override.Origins != nil {
+		o.Origins = override.Origins
+	}
+
+	if override.Methods != nil {
+		o.Methods = override.Methods
+	}
+
+	if override.Headers != nil {
+		o.Headers = override.Headers
+	}
+
+	if override.ExposeHeaders != nil {
+		o.ExposeHeaders = override.ExposeHeaders
+	}
+
+	if override.Credentials != nil {
+		o.Credentials = override.Credentials
+	}
+
+	if override.MaxAge != 0 {
+		o.MaxAge = override.MaxAge
+	}
+
+	return o
+}
+
+// isSet tells whether any of the options is set, following the rules of merge
+func (o CORSOptions) isSet() bool {
+	return o.Origins != nil || o.Methods != nil || o.Headers != nil || o.ExposeHeaders != nil ||
+		o.Credentials != nil || o.MaxAge != 0
+}
+
 func (o *CORSOptions) Validate() error {
+	return v.ValidateStruct(o,
+		v.Field(&o.Origins, v.Each(v.By(validateOrigin))),
+		v.Field(&o.Methods, v.Each(v.In(httpMethods...).Error("must be an HTTP method in upper case, e.g. GET"))),
+		v.Field(&o.Credentials, v.By(o.validateCredentials)),
+		v.Field(&o.MaxAge, v.Min(0)),
+	)
+}
+
+// validateCredentials rejects credentials with the wildcard origin, which browsers refuse
+func (o *CORSOptions) validateCredentials(value interface{}) error {
+	credentials, _ := value.(*bool)
+	if credentials == nil || !*credentials {
+		return nil
+	}
+
+	for _, origin := range o.Origins {
+		if origin == wildcardOrigin {
+			return errors.New("can't be used with the * origin")
+		}
+	}
+
+	return nil
+}
+
+// validateOrigin checks that the origin is either the wildcard or a scheme, host and optional port
+func validateOrigin(value interface{}) error {
+	origin, _ := value.(string)
+	if origin == wildcardOrigin {
+		return nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		(u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return errors.New("must be * or an origin URL, e.g. https://example.org")
+	}
+
 	return nil
 }
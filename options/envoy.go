@@ -0,0 +1,21 @@
+package options
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type EnvoyOptions struct {
+	// Cluster is the name of the Envoy cluster the generated routes forward traffic to.
+	// Defaults to the service name.
+	Cluster string `yaml:"cluster,omitempty" json:"cluster,omitempty"`
+
+	// Format is the output format of the generated route configuration, either "yaml" or "json".
+	// Default value is "yaml".
+	Format string `yaml:"format,omitempty" json:"format,omitempty"`
+}
+
+func (o *EnvoyOptions) Validate() error {
+	return validation.ValidateStruct(o,
+		validation.Field(&o.Format, validation.In("yaml", "json").Error("Envoy output format must be either yaml or json")),
+	)
+}
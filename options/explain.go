@@ -0,0 +1,159 @@
+package options
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Source tells where the effective value of an option comes from
+type Source string
+
+const (
+	SourceDefault   Source = "default"
+	SourceFlag      Source = "flag"
+	SourceRoot      Source = "root"
+	SourceTag       Source = "tag"
+	SourcePath      Source = "path"
+	SourceOperation Source = "operation"
+)
+
+// explainedOptions are the options that can be overridden on the tag, path and operation level, in the order they are explained
+var explainedOptions = []string{
+	"disabled",
+	"host",
+	"cors.origins",
+	"cors.methods",
+	"cors.headers",
+	"cors.expose_headers",
+	"cors.credentials",
+	"cors.max_age",
+	"rate_limits.rps",
+	"rate_limits.burst",
+	"rate_limits.group",
+	"timeouts.request_timeout",
+	"timeouts.idle_timeout",
+}
+
+// ExplainedOption is the effective value of an option for an operation and where it comes from
+type ExplainedOption struct {
+	// Name is the option key, e.g. rate_limits.rps
+	Name string
+
+	// Value is the effective value, empty if the option isn't set
+	Value string
+
+	Source Source
+}
+
+// Explain returns the effective options of the operation and where each of them comes from.
+// root are the options set in the root x-kusk extension, before flags are applied,
+// flagChanged tells whether the option with the given key was set with a flag.
+func (o *Options) Explain(root *Options, path, method string, flagChanged func(name string) bool) ([]ExplainedOption, error) {
+	disabled := o.IsOperationDisabled(path, method)
+
+	effective, err := flatten(SubOptions{
+		Disabled:   &disabled,
+		Host:       o.GetHost(path, method),
+		CORS:       o.GetCORSOpts(path, method),
+		RateLimits: o.GetRateLimitOpts(path, method),
+		Timeouts:   o.GetTimeoutOpts(path, method),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type level struct {
+		source  Source
+		options interface{}
+	}
+
+	// levels in order of precedence, the tags listed later on the operation take precedence
+	levels := []level{
+		{SourceOperation, o.OperationSubOptions[method+path]},
+		{SourcePath, o.PathSubOptions[path]},
+	}
+
+	tagSubOptions := o.operationTagSubOptions(path, method)
+	for i := len(tagSubOptions) - 1; i >= 0; i-- {
+		levels = append(levels, level{SourceTag, tagSubOptions[i]})
+	}
+
+	levels = append(levels, level{SourceRoot, root})
+
+	setOn := make([]map[string]string, len(levels))
+	for i, level := range levels {
+		if setOn[i], err = flatten(level.options); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]ExplainedOption, 0, len(explainedOptions))
+
+	for _, name := range explainedOptions {
+		option := ExplainedOption{
+			Name:   name,
+			Value:  effective[name],
+			Source: SourceDefault,
+		}
+
+		for i, level := range levels {
+			// flags override the root x-kusk extension only
+			if level.source == SourceRoot && flagChanged(name) {
+				option.Source = SourceFlag
+				break
+			}
+
+			if _, ok := setOn[i][name]; ok {
+				option.Source = level.source
+				break
+			}
+		}
+
+		res = append(res, option)
+	}
+
+	return res, nil
+}
+
+// flatten returns the options set in v keyed by their dot-separated path, e.g. rate_limits.rps,
+// with lists joined by commas
+func flatten(v interface{}) (map[string]string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	res := map[string]string{}
+	flattenInto(res, "", values)
+
+	return res, nil
+}
+
+func flattenInto(res map[string]string, prefix string, values map[string]interface{}) {
+	for key, value := range values {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			flattenInto(res, prefix+key+".", value)
+		case []interface{}:
+			items := make([]string, 0, len(value))
+			for _, item := range value {
+				items = append(items, fmt.Sprint(item))
+			}
+
+			res[prefix+key] = strings.Join(items, ",")
+		default:
+			res[prefix+key] = fmt.Sprint(value)
+		}
+	}
+}
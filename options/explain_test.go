@@ -0,0 +1,64 @@
+package options
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	trueValue := true
+
+	root := &Options{
+		Host:       "books.example.org",
+		RateLimits: RateLimitOptions{RPS: 100, Burst: 150},
+		Timeouts:   TimeoutOptions{RequestTimeout: Duration(10 * time.Second)},
+	}
+
+	// root options with flags applied
+	opts := *root
+	opts.RateLimits.Burst = 50
+	opts.PathSubOptions = map[string]SubOptions{
+		"/books/{id}": {Timeouts: TimeoutOptions{RequestTimeout: Duration(2 * time.Second)}},
+	}
+	opts.OperationSubOptions = map[string]SubOptions{
+		"GET/books/{id}": {
+			Disabled: &trueValue,
+			CORS:     CORSOptions{Origins: []string{"http://foo.example", "http://bar.example"}},
+		},
+	}
+	opts.TagSubOptions = map[string]SubOptions{
+		"books": {Host: "books.internal", RateLimits: RateLimitOptions{Group: "books"}},
+		"admin": {
+			RateLimits: RateLimitOptions{Group: "admin"},
+			Timeouts:   TimeoutOptions{RequestTimeout: Duration(30 * time.Second), IdleTimeout: Duration(60 * time.Second)},
+		},
+	}
+	opts.OperationTags = map[string][]string{
+		"GET/books/{id}": {"books", "admin"},
+	}
+
+	flagChanged := func(name string) bool {
+		return name == "rate_limits.burst"
+	}
+
+	actual, err := opts.Explain(root, "/books/{id}", "GET", flagChanged)
+	require.NoError(t, err)
+
+	require.Equal(t, []ExplainedOption{
+		{Name: "disabled", Value: "true", Source: SourceOperation},
+		{Name: "host", Value: "books.internal", Source: SourceTag},
+		{Name: "cors.origins", Value: "http://foo.example,http://bar.example", Source: SourceOperation},
+		{Name: "cors.methods", Source: SourceDefault},
+		{Name: "cors.headers", Source: SourceDefault},
+		{Name: "cors.expose_headers", Source: SourceDefault},
+		{Name: "cors.credentials", Source: SourceDefault},
+		{Name: "cors.max_age", Source: SourceDefault},
+		{Name: "rate_limits.rps", Value: "100", Source: SourceRoot},
+		{Name: "rate_limits.burst", Value: "50", Source: SourceFlag},
+		{Name: "rate_limits.group", Value: "admin", Source: SourceTag},
+		{Name: "timeouts.request_timeout", Value: "2s", Source: SourcePath},
+		{Name: "timeouts.idle_timeout", Value: "60s", Source: SourceTag},
+	}, actual)
+}
@@ -0,0 +1,16 @@
+package options
+
+type GatewayAPIOptions struct {
+	// GatewayName is the name of the Gateway the generated HTTPRoutes attach to.
+	GatewayName string `yaml:"gateway_name,omitempty" json:"gateway_name,omitempty"`
+
+	// GatewayNamespace is the namespace of the Gateway. Defaults to the namespace of the generated resources.
+	GatewayNamespace string `yaml:"gateway_namespace,omitempty" json:"gateway_namespace,omitempty"`
+
+	// SectionName optionally restricts the attachment to a single Gateway listener.
+	SectionName string `yaml:"section_name,omitempty" json:"section_name,omitempty"`
+}
+
+func (o *GatewayAPIOptions) Validate() error {
+	return nil
+}
@@ -0,0 +1,11 @@
+package options
+
+type IstioOptions struct {
+	// Gateways is a list of Istio Gateways the generated VirtualService applies to.
+	// If empty, the VirtualService applies to sidecars in the mesh only.
+	Gateways []string `yaml:"gateways,omitempty" json:"gateways,omitempty"`
+}
+
+func (o *IstioOptions) Validate() error {
+	return nil
+}
@@ -0,0 +1,67 @@
+package options
+
+import (
+	"fmt"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+type MetadataOptions struct {
+	// Labels are added to every generated resource.
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+
+	// Annotations are added to every generated resource.
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+
+	// NamePrefix is prepended to the name of every generated resource.
+	NamePrefix string `yaml:"name_prefix,omitempty" json:"name_prefix,omitempty"`
+
+	// NameSuffix is appended to the name of every generated resource.
+	NameSuffix string `yaml:"name_suffix,omitempty" json:"name_suffix,omitempty"`
+
+	// Ownership adds the app.kubernetes.io/managed-by: kusk-gen label to every generated resource,
+	// along with labels holding the title and the version of the OpenAPI spec.
+	Ownership bool `yaml:"ownership,omitempty" json:"ownership,omitempty"`
+}
+
+// ResourceName returns the name of a generated resource with NamePrefix and NameSuffix applied
+func (o *MetadataOptions) ResourceName(name string) string {
+	return o.NamePrefix + name + o.NameSuffix
+}
+
+func (o *MetadataOptions) Validate() error {
+	return validation.ValidateStruct(o,
+		validation.Field(&o.Labels, validation.By(validateLabels)),
+		validation.Field(&o.Annotations, validation.By(validateAnnotationKeys)),
+	)
+}
+
+func validateLabels(value interface{}) error {
+	labels, _ := value.(map[string]string)
+
+	for key, val := range labels {
+		if errs := k8svalidation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+
+		if errs := k8svalidation.IsValidLabelValue(val); len(errs) > 0 {
+			return fmt.Errorf("invalid value %q of label %q: %s", val, key, strings.Join(errs, "; "))
+		}
+	}
+
+	return nil
+}
+
+func validateAnnotationKeys(value interface{}) error {
+	annotations, _ := value.(map[string]string)
+
+	for key := range annotations {
+		if errs := k8svalidation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation key %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+
+	return nil
+}
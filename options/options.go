@@ -1,10 +1,15 @@
 package options
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
 	v "github.com/go-ozzo/ozzo-validation/v4"
 )
 
-// SubOptions allow user to overwrite certain options at path/operation level
+// SubOptions allow user to overwrite certain options at tag/path/operation level
 // using x-kusk extension
 type SubOptions struct {
 	Disabled *bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
@@ -39,6 +44,18 @@ type Options struct {
 	// NGINXIngress is a set of custom nginx-ingress options.
 	NGINXIngress NGINXIngressOptions `yaml:"nginx_ingress,omitempty" json:"nginx_ingress,omitempty"`
 
+	// GatewayAPI is a set of custom Kubernetes Gateway API options.
+	GatewayAPI GatewayAPIOptions `yaml:"gateway_api,omitempty" json:"gateway_api,omitempty"`
+
+	// Istio is a set of custom Istio options.
+	Istio IstioOptions `yaml:"istio,omitempty" json:"istio,omitempty"`
+
+	// Envoy is a set of custom options for the standalone Envoy route configuration.
+	Envoy EnvoyOptions `yaml:"envoy,omitempty" json:"envoy,omitempty"`
+
+	// Metadata is a set of labels, annotations and name changes applied to every generated resource.
+	Metadata MetadataOptions `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+
 	// PathSubOptions allow to overwrite specific subset of Options for a given path.
 	// They are filled during extension parsing, the map key is path.
 	PathSubOptions map[string]SubOptions `yaml:"-" json:"-"`
@@ -47,6 +64,15 @@ type Options struct {
 	// They are filled during extension parsing, the map key is method+path.
 	OperationSubOptions map[string]SubOptions `yaml:"-" json:"-"`
 
+	// TagSubOptions allow to overwrite specific subset of Options for the operations with a given tag,
+	// taking precedence over the global level and overridden by the path and operation levels.
+	// They are filled during extension parsing, the map key is tag name.
+	TagSubOptions map[string]SubOptions `yaml:"-" json:"-"`
+
+	// OperationTags are the tags of the operations having any tag with TagSubOptions, in the order they are listed on the operation.
+	// They are filled during extension parsing, the map key is method+path.
+	OperationTags map[string][]string `yaml:"-" json:"-"`
+
 	RateLimits RateLimitOptions `yaml:"rate_limits,omitempty" json:"rate_limits,omitempty"`
 
 	Timeouts TimeoutOptions `yaml:"timeouts,omitempty" json:"timeouts,omitempty"`
@@ -85,17 +111,129 @@ func (o *Options) Validate() error {
 func (o *Options) FillDefaultsAndValidate() error {
 	o.fillDefaults()
 
-	return v.Validate([]v.Validatable{
+	err := v.Validate([]v.Validatable{
 		o,
 		&o.Service,
 		&o.Path,
 		&o.Cluster,
-		&o.CORS,
 		&o.NGINXIngress,
-		&o.RateLimits,
-		&o.Timeouts,
+		&o.GatewayAPI,
+		&o.Istio,
+		&o.Envoy,
+		&o.Metadata,
+	})
+	if err != nil {
+		return err
+	}
+
+	return o.ValidateLevels()
+}
+
+// ValidateLevels validates CORS, rate limit and timeout options on the root, tag, path and operation level.
+// Options set on a tag, path or operation are validated merged with the levels above,
+// the errors name the level they were found on.
+func (o *Options) ValidateLevels() error {
+	var errs []string
+
+	o.walkLevels(func(level string, set SubOptions, cors CORSOptions, rateLimits RateLimitOptions, timeouts TimeoutOptions) {
+		levelErrs := v.Errors{}
+
+		if set.CORS.isSet() {
+			levelErrs["cors"] = cors.Validate()
+		}
+
+		if set.RateLimits != (RateLimitOptions{}) {
+			levelErrs["rate_limits"] = rateLimits.Validate()
+		}
+
+		if set.Timeouts != (TimeoutOptions{}) {
+			levelErrs["timeouts"] = timeouts.Validate()
+		}
+
+		if err := levelErrs.Filter(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", level, err))
+		}
 	})
 
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// Warnings returns the problems with the options on the root, tag, path and operation level
+// that don't prevent generating resources, e.g. an idle timeout less than the request timeout.
+func (o *Options) Warnings() []string {
+	var warnings []string
+
+	o.walkLevels(func(level string, set SubOptions, _ CORSOptions, _ RateLimitOptions, timeouts TimeoutOptions) {
+		if set.Timeouts == (TimeoutOptions{}) {
+			return
+		}
+
+		if warning := timeouts.warning(); warning != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: timeouts: %s", level, warning))
+		}
+	})
+
+	return warnings
+}
+
+// walkLevels calls fn for the root level and every tag, path and operation with sub-options set,
+// passing the options set on the level and the CORS, rate limit and timeout options merged with the levels above
+func (o *Options) walkLevels(fn func(level string, set SubOptions, cors CORSOptions, rateLimits RateLimitOptions, timeouts TimeoutOptions)) {
+	fn("root options", SubOptions{CORS: o.CORS, RateLimits: o.RateLimits, Timeouts: o.Timeouts}, o.CORS, o.RateLimits, o.Timeouts)
+
+	for _, tag := range sortedKeys(o.TagSubOptions) {
+		tagSubOptions := o.TagSubOptions[tag]
+
+		fn(
+			"tag "+tag,
+			tagSubOptions,
+			o.CORS.merge(tagSubOptions.CORS),
+			o.RateLimits.merge(tagSubOptions.RateLimits),
+			o.Timeouts.merge(tagSubOptions.Timeouts),
+		)
+	}
+
+	for _, path := range sortedKeys(o.PathSubOptions) {
+		fn(
+			"path "+path,
+			o.PathSubOptions[path],
+			o.GetCORSOpts(path, ""),
+			o.GetRateLimitOpts(path, ""),
+			o.GetTimeoutOpts(path, ""),
+		)
+	}
+
+	for _, key := range sortedKeys(o.OperationSubOptions) {
+		i := strings.Index(key, "/")
+		if i < 0 {
+			continue
+		}
+
+		method, path := key[:i], key[i:]
+
+		fn(
+			fmt.Sprintf("operation %s %s", method, path),
+			o.OperationSubOptions[key],
+			o.GetCORSOpts(path, method),
+			o.GetRateLimitOpts(path, method),
+			o.GetTimeoutOpts(path, method),
+		)
+	}
+}
+
+func sortedKeys(subOptions map[string]SubOptions) []string {
+	res := make([]string, 0, len(subOptions))
+	for key := range subOptions {
+		res = append(res, key)
+	}
+
+	sort.Strings(res)
+
+	return res
 }
 
 func (o *Options) IsOperationDisabled(path, method string) bool {
@@ -107,7 +245,19 @@ func (o *Options) IsOperationDisabled(path, method string) bool {
 	}
 
 	// No explicit value set for `Disabled` at the operation level, check the path level
-	return o.IsPathDisabled(path)
+	if pathSubOptions, ok := o.PathSubOptions[path]; ok && pathSubOptions.Disabled != nil {
+		return *pathSubOptions.Disabled
+	}
+
+	// Then the operation's tags, the ones listed later take precedence
+	tagSubOptions := o.operationTagSubOptions(path, method)
+	for i := len(tagSubOptions) - 1; i >= 0; i-- {
+		if tagSubOptions[i].Disabled != nil {
+			return *tagSubOptions[i].Disabled
+		}
+	}
+
+	return o.Disabled
 }
 
 func (o *Options) IsPathDisabled(path string) bool {
@@ -120,3 +270,73 @@ func (o *Options) IsPathDisabled(path string) bool {
 
 	return o.Disabled
 }
+
+// GetHost returns the host of the operation, operation level host takes precedence over the path level one,
+// which takes precedence over the operation's tags
+func (o *Options) GetHost(path, method string) string {
+	if opSubOptions, ok := o.OperationSubOptions[method+path]; ok && opSubOptions.Host != "" {
+		return opSubOptions.Host
+	}
+
+	if pathSubOptions, ok := o.PathSubOptions[path]; ok && pathSubOptions.Host != "" {
+		return pathSubOptions.Host
+	}
+
+	tagSubOptions := o.operationTagSubOptions(path, method)
+	for i := len(tagSubOptions) - 1; i >= 0; i-- {
+		if tagSubOptions[i].Host != "" {
+			return tagSubOptions[i].Host
+		}
+	}
+
+	return o.Host
+}
+
+// GetOperationSubOptions returns the options set on the operation's tags and the operation itself, merged field by field,
+// and false if neither sets any. Unlike the other getters it leaves out the path and global level options.
+func (o *Options) GetOperationSubOptions(path, method string) (SubOptions, bool) {
+	var res SubOptions
+
+	tagSubOptions := o.operationTagSubOptions(path, method)
+	for _, subOptions := range tagSubOptions {
+		res = res.merge(subOptions)
+	}
+
+	opSubOptions, ok := o.OperationSubOptions[method+path]
+	if ok {
+		res = res.merge(opSubOptions)
+	}
+
+	return res, ok || len(tagSubOptions) > 0
+}
+
+// operationTagSubOptions returns the options of the operation's tags in the order the tags are listed on the operation,
+// none for the path level, i.e. an empty method
+func (o *Options) operationTagSubOptions(path, method string) []SubOptions {
+	var res []SubOptions
+
+	for _, tag := range o.OperationTags[method+path] {
+		if tagSubOptions, ok := o.TagSubOptions[tag]; ok {
+			res = append(res, tagSubOptions)
+		}
+	}
+
+	return res
+}
+
+// merge returns the options with the fields set in override replaced
+func (o SubOptions) merge(override SubOptions) SubOptions {
+	if override.Disabled != nil {
+		o.Disabled = override.Disabled
+	}
+
+	if override.Host != "" {
+		o.Host = override.Host
+	}
+
+	o.CORS = o.CORS.merge(override.CORS)
+	o.RateLimits = o.RateLimits.merge(override.RateLimits)
+	o.Timeouts = o.Timeouts.merge(override.Timeouts)
+
+	return o
+}
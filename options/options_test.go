@@ -0,0 +1,153 @@
+package options
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLevels(t *testing.T) {
+	trueValue := true
+
+	var testCases = []struct {
+		name string
+		opts Options
+		err  string
+	}{
+		{
+			name: "valid options",
+			opts: Options{
+				CORS: CORSOptions{
+					Origins:     []string{"http://foo.example", "https://bar.example:8443"},
+					Methods:     []string{"GET", "POST"},
+					Credentials: &trueValue,
+				},
+				RateLimits: RateLimitOptions{RPS: 100, Burst: 150},
+				Timeouts:   TimeoutOptions{RequestTimeout: Duration(10 * time.Second), IdleTimeout: Duration(60 * time.Second)},
+				PathSubOptions: map[string]SubOptions{
+					"/books": {Timeouts: TimeoutOptions{RequestTimeout: Duration(20 * time.Second)}},
+				},
+				OperationSubOptions: map[string]SubOptions{
+					"GET/books": {RateLimits: RateLimitOptions{Burst: 200}},
+				},
+			},
+		},
+		{
+			name: "invalid root options",
+			opts: Options{
+				CORS: CORSOptions{
+					Origins:     []string{"*", "foo.example"},
+					Methods:     []string{"get"},
+					Credentials: &trueValue,
+				},
+				RateLimits: RateLimitOptions{Burst: 10},
+			},
+			err: "root options: cors: (credentials: can't be used with the * origin; " +
+				"methods: (0: must be an HTTP method in upper case, e.g. GET.); " +
+				"origins: (1: must be * or an origin URL, e.g. https://example.org.).); " +
+				"rate_limits: (rps: is required when burst is set.).",
+		},
+		{
+			name: "invalid merged path and operation options",
+			opts: Options{
+				RateLimits: RateLimitOptions{RPS: 100, Burst: 150},
+				Timeouts:   TimeoutOptions{IdleTimeout: Duration(30 * time.Second)},
+				PathSubOptions: map[string]SubOptions{
+					"/books": {Timeouts: TimeoutOptions{RequestTimeout: Duration(-60 * time.Second)}},
+				},
+				OperationSubOptions: map[string]SubOptions{
+					"POST/books": {RateLimits: RateLimitOptions{RPS: 200}},
+				},
+			},
+			err: "path /books: timeouts: (request_timeout: must not be negative.).; " +
+				"operation POST /books: rate_limits: (burst: must not be less than rps.).",
+		},
+		{
+			name: "invalid tag options",
+			opts: Options{
+				RateLimits: RateLimitOptions{RPS: 100},
+				TagSubOptions: map[string]SubOptions{
+					"admin": {RateLimits: RateLimitOptions{Burst: 10}},
+				},
+			},
+			err: "tag admin: rate_limits: (burst: must not be less than rps.).",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.opts.ValidateLevels()
+			if testCase.err == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, testCase.err)
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	r := require.New(t)
+
+	opts := Options{
+		Timeouts: TimeoutOptions{RequestTimeout: Duration(10 * time.Second), IdleTimeout: Duration(30 * time.Second)},
+		PathSubOptions: map[string]SubOptions{
+			"/books":   {Timeouts: TimeoutOptions{RequestTimeout: Duration(60 * time.Second)}},
+			"/authors": {RateLimits: RateLimitOptions{RPS: 10}},
+		},
+		OperationSubOptions: map[string]SubOptions{
+			"POST/authors": {Timeouts: TimeoutOptions{RequestTimeout: Duration(5 * time.Second)}},
+		},
+	}
+
+	// idle timeouts less than request timeouts were accepted before and are only warned about
+	r.NoError(opts.ValidateLevels())
+	r.Equal([]string{
+		"path /books: timeouts: idle_timeout 30s is less than request_timeout 60s, responses taking longer than idle_timeout to start may be cut off",
+	}, opts.Warnings())
+}
+
+func TestTagSubOptions(t *testing.T) {
+	r := require.New(t)
+
+	trueValue, falseValue := true, false
+
+	opts := Options{
+		Host:       "books.example.org",
+		RateLimits: RateLimitOptions{RPS: 100},
+		TagSubOptions: map[string]SubOptions{
+			"admin":    {Disabled: &trueValue, Host: "admin.example.org", RateLimits: RateLimitOptions{RPS: 10, Group: "admin"}},
+			"internal": {RateLimits: RateLimitOptions{RPS: 5}},
+		},
+		PathSubOptions: map[string]SubOptions{
+			"/admin/health": {Disabled: &falseValue, RateLimits: RateLimitOptions{RPS: 50}},
+		},
+		OperationTags: map[string][]string{
+			"GET/admin/users":  {"admin", "internal"},
+			"GET/admin/health": {"admin"},
+		},
+	}
+
+	// tags override the global level, the ones listed later take precedence
+	r.True(opts.IsOperationDisabled("/admin/users", "GET"))
+	r.Equal("admin.example.org", opts.GetHost("/admin/users", "GET"))
+	r.Equal(RateLimitOptions{RPS: 5, Group: "admin"}, opts.GetRateLimitOpts("/admin/users", "GET"))
+
+	// the path level overrides tags
+	r.False(opts.IsOperationDisabled("/admin/health", "GET"))
+	r.Equal(RateLimitOptions{RPS: 50, Group: "admin"}, opts.GetRateLimitOpts("/admin/health", "GET"))
+
+	// tags don't apply to the path level and other operations
+	r.Equal(RateLimitOptions{RPS: 100}, opts.GetRateLimitOpts("/admin/users", ""))
+	r.False(opts.IsOperationDisabled("/admin/users", "POST"))
+	r.Equal("books.example.org", opts.GetHost("/admin/users", "POST"))
+
+	subOptions, ok := opts.GetOperationSubOptions("/admin/users", "GET")
+	r.True(ok)
+	r.Equal(SubOptions{Disabled: &trueValue, Host: "admin.example.org", RateLimits: RateLimitOptions{RPS: 5, Group: "admin"}}, subOptions)
+
+	_, ok = opts.GetOperationSubOptions("/admin/users", "POST")
+	r.False(ok)
+}
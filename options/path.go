@@ -1,6 +1,8 @@
 package options
 
 import (
+	"strings"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
@@ -21,6 +23,11 @@ type PathOptions struct {
 
 	// Split forces Kusk to generate a separate resource for each Path or Operation, where appropriate.
 	Split bool `yaml:"split,omitempty" json:"split,omitempty"`
+
+	// Prefix makes the routes match the paths of the operations as prefixes rather than exactly,
+	// i.e. given the path "/pets", the route would also match "/pets/1" and "/petsitters".
+	// Supported by the Traefik generator.
+	Prefix bool `yaml:"prefix,omitempty" json:"prefix,omitempty"`
 }
 
 func (o *PathOptions) Validate() error {
@@ -28,3 +35,23 @@ func (o *PathOptions) Validate() error {
 		validation.Field(&o.Base, validation.Required.Error("Base path required")),
 	)
 }
+
+// RewritePath applies TrimPrefix or Rewrite to the given path,
+// returning the path that the upstream service would receive
+func (o *PathOptions) RewritePath(path string) string {
+	if o.TrimPrefix != "" && strings.HasPrefix(path, o.TrimPrefix) {
+		res := strings.TrimPrefix(path, o.TrimPrefix)
+		if !strings.HasPrefix(res, "/") {
+			res = "/" + res
+		}
+
+		return res
+	}
+
+	if o.Rewrite != "" && strings.HasPrefix(path, o.Base) {
+		return strings.TrimSuffix(o.Rewrite, "/") + "/" +
+			strings.TrimPrefix(strings.TrimPrefix(path, o.Base), "/")
+	}
+
+	return path
+}
@@ -1,6 +1,8 @@
 package options
 
-import "reflect"
+import (
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
 
 type RateLimitOptions struct {
 	RPS   uint32 `json:"rps,omitempty" yaml:"rps,omitempty"`
@@ -8,29 +10,50 @@ type RateLimitOptions struct {
 	Group string `json:"group,omitempty" yaml:"group,omitempty"`
 }
 
+// GetRateLimitOpts returns rate limit options for the operation, merging global, tag, path and operation level options field by field.
+// Use an empty method to get path level options.
 func (o *Options) GetRateLimitOpts(path, method string) RateLimitOptions {
 	// take global rate limit options
 	rateLimitOpts := o.RateLimits
 
-	// if non-zero path-level rate limit options are different, override with them
-	if pathSubOpts, ok := o.PathSubOptions[path]; ok &&
-		pathSubOpts.RateLimits.ShouldOverride(rateLimitOpts) {
-		rateLimitOpts = pathSubOpts.RateLimits
+	// override with the fields set on the operation's tags
+	for _, tagSubOpts := range o.operationTagSubOptions(path, method) {
+		rateLimitOpts = rateLimitOpts.merge(tagSubOpts.RateLimits)
 	}
 
-	// if non-zero operation-level rate limit options are different, override them
-	if opSubOpts, ok := o.OperationSubOptions[path]; ok &&
-		opSubOpts.RateLimits.ShouldOverride(rateLimitOpts) {
-		rateLimitOpts = opSubOpts.RateLimits
+	// override with the fields set on the path level
+	if pathSubOpts, ok := o.PathSubOptions[path]; ok {
+		rateLimitOpts = rateLimitOpts.merge(pathSubOpts.RateLimits)
+	}
+
+	// override with the fields set on the operation level
+	if opSubOpts, ok := o.OperationSubOptions[method+path]; ok {
+		rateLimitOpts = rateLimitOpts.merge(opSubOpts.RateLimits)
 	}
 
 	return rateLimitOpts
 }
 
-func (o *RateLimitOptions) ShouldOverride(opts RateLimitOptions) bool {
-	return !reflect.DeepEqual(CORSOptions{}, o) && !reflect.DeepEqual(opts, o)
+// merge returns the options with the fields set in override replaced
+func (o RateLimitOptions) merge(override RateLimitOptions) RateLimitOptions {
+	if override.RPS != 0 {
+		o.RPS = override.RPS
+	}
+
+	if override.Burst != 0 {
+		o.Burst = override.Burst
+	}
+
+	if override.Group != "" {
+		o.Group = override.Group
+	}
+
+	return o
 }
 
 func (o *RateLimitOptions) Validate() error {
-	return nil
+	return v.ValidateStruct(o,
+		v.Field(&o.RPS, v.When(o.Burst != 0, v.Required.Error("is required when burst is set"))),
+		v.Field(&o.Burst, v.When(o.Burst != 0, v.Min(o.RPS).Error("must not be less than rps"))),
+	)
 }
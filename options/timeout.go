@@ -1,37 +1,154 @@
 package options
 
-import "reflect"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	v "github.com/go-ozzo/ozzo-validation/v4"
+)
 
 type TimeoutOptions struct {
 	// RequestTimeout is total request timeout
-	RequestTimeout uint32 `yaml:"request_timeout,omitempty" json:"request_timeout,omitempty"`
+	RequestTimeout Duration `yaml:"request_timeout,omitempty" json:"request_timeout,omitempty"`
 	// IdleTimeout is timeout for idle connection
-	IdleTimeout uint32 `yaml:"idle_timeout,omitempty" json:"idle_timeout,omitempty"`
+	IdleTimeout Duration `yaml:"idle_timeout,omitempty" json:"idle_timeout,omitempty"`
+}
+
+// Duration is a timeout given either as a number of seconds, e.g. 30 or 0.25,
+// or as a duration string, e.g. 250ms, 30s or 2m.
+// It implements pflag.Value to be used as a flag.
+type Duration time.Duration
+
+// ParseDuration parses a number of seconds or a duration string
+func ParseDuration(s string) (Duration, error) {
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		return Duration(seconds * float64(time.Second)), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is neither a number of seconds nor a duration, e.g. 250ms", s)
+	}
+
+	return Duration(d), nil
+}
+
+// String returns the duration in whole seconds or milliseconds if possible, e.g. 30s or 250ms,
+// in a format understood by Go and Kubernetes
+func (d Duration) String() string {
+	switch {
+	case d%Duration(time.Second) == 0:
+		return fmt.Sprintf("%ds", d/Duration(time.Second))
+	case d%Duration(time.Millisecond) == 0:
+		return fmt.Sprintf("%dms", d/Duration(time.Millisecond))
+	default:
+		return time.Duration(d).String()
+	}
+}
+
+// Seconds returns the duration as a floating point number of seconds
+func (d Duration) Seconds() float64 {
+	return time.Duration(d).Seconds()
+}
+
+// Milliseconds returns the duration as an integer millisecond count
+func (d Duration) Milliseconds() int64 {
+	return time.Duration(d).Milliseconds()
+}
+
+func (d *Duration) Set(s string) error {
+	res, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = res
+
+	return nil
+}
+
+func (d *Duration) Type() string {
+	return "duration"
 }
 
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+
+	switch value := value.(type) {
+	case float64:
+		*d = Duration(value * float64(time.Second))
+	case string:
+		return d.Set(value)
+	default:
+		return fmt.Errorf("%s is neither a number of seconds nor a duration, e.g. 250ms", b)
+	}
+
+	return nil
+}
+
+// GetTimeoutOpts returns timeout options for the operation, merging global, tag, path and operation level options field by field.
+// Use an empty method to get path level options.
 func (o *Options) GetTimeoutOpts(path, method string) TimeoutOptions {
 	// take global timeout options
 	timeoutOpts := o.Timeouts
 
-	// if non-zero path-level timeout options are different, override them
+	// override with the fields set on the operation's tags
+	for _, tagSubOpts := range o.operationTagSubOptions(path, method) {
+		timeoutOpts = timeoutOpts.merge(tagSubOpts.Timeouts)
+	}
+
+	// override with the fields set on the path level
 	if pathSubOpts, ok := o.PathSubOptions[path]; ok {
-		if !reflect.DeepEqual(TimeoutOptions{}, pathSubOpts.Timeouts) &&
-			!reflect.DeepEqual(timeoutOpts, pathSubOpts.Timeouts) {
-			timeoutOpts = pathSubOpts.Timeouts
-		}
+		timeoutOpts = timeoutOpts.merge(pathSubOpts.Timeouts)
 	}
 
-	// if non-zero operation-level timeout options are different, override them
-	if opSubOpts, ok := o.OperationSubOptions[path]; ok {
-		if !reflect.DeepEqual(TimeoutOptions{}, opSubOpts.Timeouts) &&
-			!reflect.DeepEqual(timeoutOpts, opSubOpts.Timeouts) {
-			timeoutOpts = opSubOpts.Timeouts
-		}
+	// override with the fields set on the operation level
+	if opSubOpts, ok := o.OperationSubOptions[method+path]; ok {
+		timeoutOpts = timeoutOpts.merge(opSubOpts.Timeouts)
 	}
 
 	return timeoutOpts
 }
 
+// merge returns the options with the fields set in override replaced
+func (o TimeoutOptions) merge(override TimeoutOptions) TimeoutOptions {
+	if override.RequestTimeout != 0 {
+		o.RequestTimeout = override.RequestTimeout
+	}
+
+	if override.IdleTimeout != 0 {
+		o.IdleTimeout = override.IdleTimeout
+	}
+
+	return o
+}
+
 func (o *TimeoutOptions) Validate() error {
-	return nil
+	return v.ValidateStruct(o,
+		v.Field(&o.RequestTimeout, v.Min(Duration(0)).Error("must not be negative")),
+		v.Field(&o.IdleTimeout, v.Min(Duration(0)).Error("must not be negative")),
+	)
+}
+
+// warning describes timeouts that are valid but likely unintended, it's empty if there's nothing to warn about.
+// An idle timeout less than the request timeout used to be accepted and is kept valid,
+// yet responses that take longer than the idle timeout to start are cut off before the request timeout.
+func (o *TimeoutOptions) warning() string {
+	if o.IdleTimeout > 0 && o.RequestTimeout > 0 && o.IdleTimeout < o.RequestTimeout {
+		return fmt.Sprintf(
+			"idle_timeout %s is less than request_timeout %s, responses taking longer than idle_timeout to start may be cut off",
+			o.IdleTimeout, o.RequestTimeout,
+		)
+	}
+
+	return ""
 }
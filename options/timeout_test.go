@@ -0,0 +1,55 @@
+package options
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	var testCases = []struct {
+		value string
+		res   Duration
+		str   string
+		err   string
+	}{
+		{value: "30", res: Duration(30 * time.Second), str: "30s"},
+		{value: "0.25", res: Duration(250 * time.Millisecond), str: "250ms"},
+		{value: "1500ms", res: Duration(1500 * time.Millisecond), str: "1500ms"},
+		{value: "2m", res: Duration(2 * time.Minute), str: "120s"},
+		{value: "1.5us", res: Duration(1500 * time.Nanosecond), str: "1.5µs"},
+		{value: "5 seconds", err: `"5 seconds" is neither a number of seconds nor a duration, e.g. 250ms`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			r := require.New(t)
+
+			res, err := ParseDuration(testCase.value)
+			if testCase.err != "" {
+				r.EqualError(err, testCase.err)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal(testCase.res, res)
+			r.Equal(testCase.str, res.String())
+		})
+	}
+}
+
+func TestTimeoutOptionsJSON(t *testing.T) {
+	r := require.New(t)
+
+	var res TimeoutOptions
+	r.NoError(json.Unmarshal([]byte(`{"request_timeout": 1.5, "idle_timeout": "2m"}`), &res))
+	r.Equal(TimeoutOptions{RequestTimeout: Duration(1500 * time.Millisecond), IdleTimeout: Duration(2 * time.Minute)}, res)
+
+	b, err := json.Marshal(res)
+	r.NoError(err)
+	r.JSONEq(`{"request_timeout": "1500ms", "idle_timeout": "120s"}`, string(b))
+
+	r.Error(json.Unmarshal([]byte(`{"request_timeout": true}`), &res))
+}
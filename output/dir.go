@@ -0,0 +1,208 @@
+package output
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubeshop/kusk-gen/generators"
+)
+
+const (
+	// generatedHeader marks files written by kusk-gen, only those files are overwritten or pruned
+	generatedHeader = "# Code generated by kusk-gen. DO NOT EDIT."
+
+	kustomizationFileName = "kustomization.yaml"
+)
+
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Resources  []string `json:"resources"`
+}
+
+// MarshalFunc returns the content of a file holding the resources of the result,
+// in the format of the generator they were generated by, see generators.Marshal
+type MarshalFunc func(res *generators.Result) (string, error)
+
+// WriteDir writes each generated resource into its own <kind>-<name>.yaml file in dir,
+// optionally along with a kustomization.yaml listing them.
+// The resources are marshaled with marshal, as YAML if it's nil. Other formats, e.g. the JSON output of Envoy,
+// are written to .yaml files too, as YAML is a superset of JSON and the files start with a YAML comment.
+// Files generated by previous runs that are not part of the result are removed.
+func WriteDir(dir string, res *generators.Result, marshal MarshalFunc, withKustomization bool) error {
+	if marshal == nil {
+		marshal = (*generators.Result).YAML
+	}
+
+	fileNames, objectsByFile := groupByFile(res.Objects)
+
+	files := make([]generatedFile, 0, len(fileNames)+1)
+
+	for _, fileName := range fileNames {
+		content, err := marshal(&generators.Result{Objects: objectsByFile[fileName]})
+		if err != nil {
+			return err
+		}
+
+		files = append(files, generatedFile{name: fileName, content: content})
+	}
+
+	if withKustomization {
+		b, err := yaml.Marshal(kustomization{
+			APIVersion: "kustomize.config.k8s.io/v1beta1",
+			Kind:       "Kustomization",
+			Resources:  fileNames,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to marshal kustomization: %w", err)
+		}
+
+		files = append(files, generatedFile{name: kustomizationFileName, content: string(b)})
+	}
+
+	return writeGenerated(dir, files)
+}
+
+// groupByFile groups the objects by the name of the file they are written to,
+// resources with the same kind and name end up in the same file
+func groupByFile(objects []*unstructured.Unstructured) ([]string, map[string][]*unstructured.Unstructured) {
+	var fileNames []string
+	objectsByFile := map[string][]*unstructured.Unstructured{}
+
+	for _, obj := range objects {
+		fileName := FileName(obj)
+		if _, ok := objectsByFile[fileName]; !ok {
+			fileNames = append(fileNames, fileName)
+		}
+
+		objectsByFile[fileName] = append(objectsByFile[fileName], obj)
+	}
+
+	return fileNames, objectsByFile
+}
+
+type generatedFile struct {
+	name    string
+	content string
+}
+
+// writeGenerated writes the files into dir and removes files generated by previous runs that are not among them
+func writeGenerated(dir string, files []generatedFile) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	written := map[string]bool{}
+
+	for _, file := range files {
+		if err := writeFile(filepath.Join(dir, file.name), file.content); err != nil {
+			return err
+		}
+
+		written[file.name] = true
+	}
+
+	return prune(dir, written)
+}
+
+// FileName returns the name of the file the resource is written to, i.e. <kind>-<name>.yaml
+func FileName(obj *unstructured.Unstructured) string {
+	kind := strings.ToLower(obj.GetKind())
+	if kind == "" {
+		// not a Kubernetes resource, e.g. Envoy route configuration
+		kind = "resource"
+	}
+
+	name := obj.GetName()
+	if name == "" {
+		name, _, _ = unstructured.NestedString(obj.Object, "name")
+	}
+
+	return kind + "-" + name + ".yaml"
+}
+
+// WriteFile writes the generated content into a single file, creating its directory if needed.
+// Like WriteDir, it refuses to overwrite a file not generated by kusk-gen.
+func WriteFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	return writeFile(path, content)
+}
+
+func writeFile(path, content string) error {
+	generated, err := isGenerated(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err == nil && !generated {
+		return fmt.Errorf("refusing to overwrite %s as it was not generated by kusk-gen", path)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(generatedHeader+"\n"+content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// prune removes files generated by kusk-gen that weren't written in this run
+func prune(dir string, written map[string]bool) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read output directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || written[entry.Name()] {
+			continue
+		}
+
+		if ext := filepath.Ext(entry.Name()); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		generated, err := isGenerated(path)
+		if err != nil {
+			return err
+		}
+
+		if !generated {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove stale file %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// isGenerated checks whether the file starts with the kusk-gen header
+func isGenerated(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	firstLine, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && firstLine == "" {
+		return false, nil
+	}
+
+	return strings.TrimSpace(firstLine) == generatedHeader, nil
+}
@@ -0,0 +1,159 @@
+package output
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/generators/envoy"
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+func newObject(kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion("example.com/v1")
+	obj.SetKind(kind)
+	obj.SetName(name)
+
+	return obj
+}
+
+func listFiles(r *require.Assertions, dir string) []string {
+	entries, err := ioutil.ReadDir(dir)
+	r.NoError(err)
+
+	var res []string
+	for _, entry := range entries {
+		res = append(res, entry.Name())
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func TestWriteDir(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+
+	// a file not generated by kusk-gen must be left alone
+	r.NoError(ioutil.WriteFile(filepath.Join(dir, "custom.yaml"), []byte("foo: bar\n"), 0644))
+
+	res := &generators.Result{
+		Objects: []*unstructured.Unstructured{
+			newObject("Mapping", "petstore-getpet"),
+			newObject("Mapping", "petstore-addpet"),
+			newObject("RateLimit", "petstore"),
+		},
+	}
+
+	r.NoError(WriteDir(dir, res, nil, true))
+	r.Equal([]string{
+		"custom.yaml",
+		"kustomization.yaml",
+		"mapping-petstore-addpet.yaml",
+		"mapping-petstore-getpet.yaml",
+		"ratelimit-petstore.yaml",
+	}, listFiles(r, dir))
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "mapping-petstore-getpet.yaml"))
+	r.NoError(err)
+	r.Equal(`# Code generated by kusk-gen. DO NOT EDIT.
+---
+apiVersion: example.com/v1
+kind: Mapping
+metadata:
+  name: petstore-getpet
+`, string(b))
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+	r.NoError(err)
+	r.Equal(`# Code generated by kusk-gen. DO NOT EDIT.
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- mapping-petstore-getpet.yaml
+- mapping-petstore-addpet.yaml
+- ratelimit-petstore.yaml
+`, string(b))
+
+	// removed operations and the kustomization are pruned on the next run
+	res.Objects = res.Objects[:1]
+
+	r.NoError(WriteDir(dir, res, nil, false))
+	r.Equal([]string{
+		"custom.yaml",
+		"mapping-petstore-getpet.yaml",
+	}, listFiles(r, dir))
+}
+
+func TestWriteDirMarshal(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+
+	res := &generators.Result{
+		Objects: []*unstructured.Unstructured{
+			{Object: map[string]interface{}{"name": "petstore"}},
+		},
+	}
+
+	opts := &options.Options{Envoy: options.EnvoyOptions{Format: "json"}}
+
+	marshal := func(res *generators.Result) (string, error) {
+		return generators.Marshal(&envoy.Generator{}, opts, res)
+	}
+
+	r.NoError(WriteDir(dir, res, marshal, false))
+	r.Equal([]string{"resource-petstore.yaml"}, listFiles(r, dir))
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "resource-petstore.yaml"))
+	r.NoError(err)
+	r.Equal(`# Code generated by kusk-gen. DO NOT EDIT.
+{
+  "name": "petstore"
+}
+`, string(b))
+}
+
+func TestWriteDirRefusesToOverwrite(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+
+	r.NoError(ioutil.WriteFile(filepath.Join(dir, "mapping-petstore.yaml"), []byte("foo: bar\n"), 0644))
+
+	res := &generators.Result{
+		Objects: []*unstructured.Unstructured{newObject("Mapping", "petstore")},
+	}
+
+	r.Error(WriteDir(dir, res, nil, false))
+}
+
+func TestFileName(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("httpproxy-petstore.yaml", FileName(newObject("HTTPProxy", "petstore")))
+
+	// non-Kubernetes resources such as Envoy route configuration only have a name
+	r.Equal("resource-petstore.yaml", FileName(&unstructured.Unstructured{
+		Object: map[string]interface{}{"name": "petstore"},
+	}))
+}
+
+func TestWriteFile(t *testing.T) {
+	r := require.New(t)
+	path := filepath.Join(t.TempDir(), "deploy", "petstore.yaml")
+
+	r.NoError(WriteFile(path, "---\nkind: Mapping\n"))
+
+	content, err := ioutil.ReadFile(path)
+	r.NoError(err)
+	r.Equal(generatedHeader+"\n---\nkind: Mapping\n", string(content))
+
+	r.NoError(ioutil.WriteFile(path, []byte("foo: bar\n"), 0644))
+	r.Error(WriteFile(path, "---\nkind: Mapping\n"))
+}
@@ -0,0 +1,347 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+const (
+	chartFileName  = "Chart.yaml"
+	valuesFileName = "values.yaml"
+	templatesDir   = "templates"
+
+	defaultChartName    = "kusk"
+	defaultChartVersion = "0.1.0"
+)
+
+var reInvalidChartNameSymbols = regexp.MustCompile(`[^a-z0-9]+`)
+
+// GenerateFunc generates the resources for the given options.
+// It is called again with placeholder values to find out where the options end up in the resources.
+type GenerateFunc func(opts *options.Options) (*generators.Result, error)
+
+// chartValue is an option that can be lifted into the chart values
+type chartValue struct {
+	// path of the value in values.yaml
+	path []string
+	// sentinel is a placeholder option value that is unlikely to appear in the resources on its own
+	sentinel string
+	numeric  bool
+
+	get func(opts *options.Options) interface{}
+	set func(opts *options.Options, sentinel string)
+}
+
+var chartValues = []chartValue{
+	{
+		path:     []string{"host"},
+		sentinel: "kuskhelmhost",
+		get:      func(opts *options.Options) interface{} { return opts.Host },
+		set:      func(opts *options.Options, s string) { opts.Host = s },
+	},
+	{
+		path:     []string{"namespace"},
+		sentinel: "kuskhelmnamespace",
+		get:      func(opts *options.Options) interface{} { return opts.Namespace },
+		set:      func(opts *options.Options, s string) { opts.Namespace = s },
+	},
+	{
+		path:     []string{"service", "name"},
+		sentinel: "kuskhelmsvcname",
+		get:      func(opts *options.Options) interface{} { return opts.Service.Name },
+		set:      func(opts *options.Options, s string) { opts.Service.Name = s },
+	},
+	{
+		path:     []string{"service", "namespace"},
+		sentinel: "kuskhelmsvcns",
+		get:      func(opts *options.Options) interface{} { return opts.Service.Namespace },
+		set:      func(opts *options.Options, s string) { opts.Service.Namespace = s },
+	},
+	{
+		path:     []string{"service", "port"},
+		sentinel: "65521",
+		numeric:  true,
+		get:      func(opts *options.Options) interface{} { return opts.Service.Port },
+		set: func(opts *options.Options, s string) {
+			port, _ := strconv.ParseInt(s, 10, 32)
+			opts.Service.Port = int32(port)
+		},
+	},
+	// burst is lifted before rps, so that burst isn't less than rps with either of the sentinels in place
+	{
+		path:     []string{"rate_limits", "burst"},
+		sentinel: "1999999003",
+		numeric:  true,
+		get:      func(opts *options.Options) interface{} { return opts.RateLimits.Burst },
+		set: func(opts *options.Options, s string) {
+			burst, _ := strconv.ParseUint(s, 10, 32)
+			opts.RateLimits.Burst = uint32(burst)
+		},
+	},
+	{
+		path:     []string{"rate_limits", "rps"},
+		sentinel: "1999999002",
+		numeric:  true,
+		get:      func(opts *options.Options) interface{} { return opts.RateLimits.RPS },
+		set: func(opts *options.Options, s string) {
+			rps, _ := strconv.ParseUint(s, 10, 32)
+			opts.RateLimits.RPS = uint32(rps)
+		},
+	},
+}
+
+// placeholder returns the template expression for the value,
+// the value is quoted when it makes up the whole YAML scalar so that it is always a string
+func (v *chartValue) placeholder(wholeScalar bool) string {
+	expr := ".Values." + strings.Join(v.path, ".")
+	if wholeScalar && !v.numeric {
+		expr += " | quote"
+	}
+
+	return "{{ " + expr + " }}"
+}
+
+// render returns the option value as it would be rendered by Helm from the placeholder
+func (v *chartValue) render(opts *options.Options, wholeScalar bool) string {
+	value := fmt.Sprint(v.get(opts))
+	if wholeScalar && !v.numeric {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+type chart struct {
+	APIVersion  string `json:"apiVersion"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"appVersion,omitempty"`
+}
+
+// WriteChart packages the generated resources into a Helm chart in dir.
+// opts are the options res was generated with, host, namespace, service and rate limit options
+// are lifted into values.yaml and replaced with references to them in the templates.
+// Options that the generator doesn't use verbatim, e.g. to compute other values, are kept as is
+// and a warning is added to res.
+func WriteChart(dir string, res *generators.Result, opts *options.Options, info *openapi3.Info, generate GenerateFunc) error {
+	expected, err := normalize(res)
+	if err != nil {
+		return err
+	}
+
+	var lifted []chartValue
+
+	for _, v := range chartValues {
+		if reflect.ValueOf(v.get(opts)).IsZero() {
+			continue
+		}
+
+		candidate := append(append([]chartValue{}, lifted...), v)
+
+		templated, err := generateTemplates(opts, candidate, generate)
+		if err == nil {
+			err = verifyTemplates(templated, expected, opts, candidate)
+		}
+
+		if err != nil {
+			res.Warn("%s can't be lifted into the chart values and is kept as is: %s", strings.Join(v.path, "."), err)
+			continue
+		}
+
+		// the generator doesn't use the option
+		if !strings.Contains(templated, v.sentinel) {
+			continue
+		}
+
+		lifted = candidate
+	}
+
+	sentinelRes, err := generateWithSentinels(opts, lifted, generate)
+	if err != nil {
+		return err
+	}
+
+	fileNames, objectsByFile := groupByFile(sentinelRes.Objects)
+
+	templates := make([]generatedFile, 0, len(fileNames))
+
+	for _, fileName := range fileNames {
+		content, err := (&generators.Result{Objects: objectsByFile[fileName]}).YAML()
+		if err != nil {
+			return err
+		}
+
+		templates = append(templates, generatedFile{
+			name: replaceSentinels(fileName, lifted, func(v *chartValue, _ bool) string {
+				return v.render(opts, false)
+			}),
+			content: replaceSentinels(content, lifted, func(v *chartValue, wholeScalar bool) string {
+				return v.placeholder(wholeScalar)
+			}),
+		})
+	}
+
+	chartFile, err := yaml.Marshal(newChart(dir, info))
+	if err != nil {
+		return fmt.Errorf("unable to marshal chart: %w", err)
+	}
+
+	valuesFile, err := yaml.Marshal(buildValues(opts, lifted))
+	if err != nil {
+		return fmt.Errorf("unable to marshal chart values: %w", err)
+	}
+
+	if err := writeGenerated(dir, []generatedFile{
+		{name: chartFileName, content: string(chartFile)},
+		{name: valuesFileName, content: string(valuesFile)},
+	}); err != nil {
+		return err
+	}
+
+	return writeGenerated(filepath.Join(dir, templatesDir), templates)
+}
+
+// generateWithSentinels generates the resources with the sentinels in place of the values
+func generateWithSentinels(opts *options.Options, values []chartValue, generate GenerateFunc) (*generators.Result, error) {
+	sentinelOpts := *opts
+
+	for _, v := range values {
+		v.set(&sentinelOpts, v.sentinel)
+	}
+
+	return generate(&sentinelOpts)
+}
+
+// generateTemplates returns the resources generated with the sentinels in place of the values as YAML
+func generateTemplates(opts *options.Options, values []chartValue, generate GenerateFunc) (string, error) {
+	sentinelRes, err := generateWithSentinels(opts, values, generate)
+	if err != nil {
+		return "", err
+	}
+
+	return sentinelRes.YAML()
+}
+
+// verifyTemplates checks that rendering the templates with the current option values
+// results in exactly the resources generated with them
+func verifyTemplates(templated string, expected *generators.Result, opts *options.Options, values []chartValue) error {
+	rendered := &generators.Result{}
+
+	err := rendered.AddYAML([]byte(replaceSentinels(templated, values, func(v *chartValue, wholeScalar bool) string {
+		return v.render(opts, wholeScalar)
+	})))
+	if err != nil {
+		return fmt.Errorf("rendered templates are not valid YAML: %w", err)
+	}
+
+	if len(rendered.Objects) != len(expected.Objects) {
+		return fmt.Errorf("rendered templates don't match the generated resources")
+	}
+
+	for i := range rendered.Objects {
+		if !reflect.DeepEqual(rendered.Objects[i].Object, expected.Objects[i].Object) {
+			return fmt.Errorf("rendered templates don't match the generated resources")
+		}
+	}
+
+	return nil
+}
+
+// normalize marshals and parses back the objects so that they can be compared to the rendered templates
+func normalize(res *generators.Result) (*generators.Result, error) {
+	b, err := res.YAML()
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := &generators.Result{}
+	if err := normalized.AddYAML([]byte(b)); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// replaceSentinels replaces the sentinels in content line by line,
+// wholeScalar tells whether the sentinel is the entire value on the line
+func replaceSentinels(content string, values []chartValue, replacement func(v *chartValue, wholeScalar bool) string) string {
+	lines := strings.Split(content, "\n")
+
+	for i, line := range lines {
+		for j := range values {
+			v := &values[j]
+			if !strings.Contains(line, v.sentinel) {
+				continue
+			}
+
+			trimmed := strings.TrimSpace(line)
+			wholeScalar := strings.HasSuffix(trimmed, ": "+v.sentinel) || trimmed == "- "+v.sentinel
+
+			line = strings.ReplaceAll(line, v.sentinel, replacement(v, wholeScalar))
+		}
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// buildValues returns the values.yaml content with the current values of the lifted options
+func buildValues(opts *options.Options, values []chartValue) map[string]interface{} {
+	res := map[string]interface{}{}
+
+	for _, v := range values {
+		m := res
+		for _, key := range v.path[:len(v.path)-1] {
+			if _, ok := m[key]; !ok {
+				m[key] = map[string]interface{}{}
+			}
+
+			m = m[key].(map[string]interface{})
+		}
+
+		m[v.path[len(v.path)-1]] = v.get(opts)
+	}
+
+	return res
+}
+
+// newChart returns the chart metadata, the chart is named after its directory
+// and versioned after the API if its version is a valid semantic version
+func newChart(dir string, info *openapi3.Info) chart {
+	name := strings.Trim(reInvalidChartNameSymbols.ReplaceAllString(strings.ToLower(filepath.Base(filepath.Clean(dir))), "-"), "-")
+	if name == "" {
+		name = defaultChartName
+	}
+
+	res := chart{
+		APIVersion: "v2",
+		Name:       name,
+		Type:       "application",
+		Version:    defaultChartVersion,
+	}
+
+	if info != nil {
+		res.Description = info.Title
+		res.AppVersion = info.Version
+
+		if _, err := version.ParseSemantic(info.Version); err == nil {
+			res.Version = info.Version
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,154 @@
+package output
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk-gen/generators"
+	"github.com/kubeshop/kusk-gen/generators/traefik"
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+// generateRateLimit generates a resource using the host and service name verbatim
+// and the rate limit to compute another value
+func generateRateLimit(opts *options.Options) (*generators.Result, error) {
+	obj := newObject("RateLimit", opts.Service.Name+"-ratelimit")
+
+	obj.Object["spec"] = map[string]interface{}{
+		"host":    opts.Host,
+		"service": opts.Service.Name + ":80",
+		"rps":     int64(opts.RateLimits.RPS),
+		"factor":  int64(opts.RateLimits.Burst / opts.RateLimits.RPS),
+	}
+
+	res := &generators.Result{}
+	res.Objects = append(res.Objects, obj)
+
+	return res, nil
+}
+
+func TestWriteChart(t *testing.T) {
+	r := require.New(t)
+	dir := filepath.Join(t.TempDir(), "Books API")
+
+	opts := &options.Options{
+		Host: "books.example.org",
+		Service: options.ServiceOptions{
+			Name: "webapp",
+		},
+		RateLimits: options.RateLimitOptions{
+			RPS:   10,
+			Burst: 20,
+		},
+	}
+
+	res, err := generateRateLimit(opts)
+	r.NoError(err)
+
+	info := &openapi3.Info{Title: "Books", Version: "1.2.0"}
+
+	r.NoError(WriteChart(dir, res, opts, info, generateRateLimit))
+	r.Equal([]string{"Chart.yaml", "templates", "values.yaml"}, listFiles(r, dir))
+	r.Equal([]string{"ratelimit-webapp-ratelimit.yaml"}, listFiles(r, filepath.Join(dir, "templates")))
+
+	chart, err := ioutil.ReadFile(filepath.Join(dir, "Chart.yaml"))
+	r.NoError(err)
+	r.Equal(`# Code generated by kusk-gen. DO NOT EDIT.
+apiVersion: v2
+appVersion: 1.2.0
+description: Books
+name: books-api
+type: application
+version: 1.2.0
+`, string(chart))
+
+	values, err := ioutil.ReadFile(filepath.Join(dir, "values.yaml"))
+	r.NoError(err)
+	r.Equal(`# Code generated by kusk-gen. DO NOT EDIT.
+host: books.example.org
+service:
+  name: webapp
+`, string(values))
+
+	template, err := ioutil.ReadFile(filepath.Join(dir, "templates", "ratelimit-webapp-ratelimit.yaml"))
+	r.NoError(err)
+	r.Equal(`# Code generated by kusk-gen. DO NOT EDIT.
+---
+apiVersion: example.com/v1
+kind: RateLimit
+metadata:
+  name: {{ .Values.service.name }}-ratelimit
+spec:
+  factor: 2
+  host: {{ .Values.host | quote }}
+  rps: 10
+  service: {{ .Values.service.name }}:80
+`, string(template))
+
+	r.Equal([]string{
+		"rate_limits.burst can't be lifted into the chart values and is kept as is: rendered templates don't match the generated resources",
+		"rate_limits.rps can't be lifted into the chart values and is kept as is: rendered templates don't match the generated resources",
+	}, res.Warnings)
+}
+
+func TestWriteChartValidatedRateLimits(t *testing.T) {
+	r := require.New(t)
+	dir := filepath.Join(t.TempDir(), "books")
+
+	spec := &openapi3.T{
+		Info: &openapi3.Info{Title: "Books", Version: "1.2.0"},
+		Paths: openapi3.Paths{
+			"/books": &openapi3.PathItem{
+				Get: &openapi3.Operation{OperationID: "listBooks"},
+			},
+		},
+	}
+
+	// the generator validates that burst isn't less than rps with the sentinels in place
+	generate := func(opts *options.Options) (*generators.Result, error) {
+		return generators.GenerateObjects(&traefik.Generator{}, opts, spec)
+	}
+
+	opts := &options.Options{
+		Namespace: "booksapp",
+		Service: options.ServiceOptions{
+			Name:      "webapp",
+			Namespace: "booksapp",
+		},
+		RateLimits: options.RateLimitOptions{
+			RPS:   10,
+			Burst: 20,
+		},
+	}
+
+	res, err := generate(opts)
+	r.NoError(err)
+
+	r.NoError(WriteChart(dir, res, opts, spec.Info, generate))
+	r.Empty(res.Warnings)
+
+	values, err := ioutil.ReadFile(filepath.Join(dir, "values.yaml"))
+	r.NoError(err)
+	r.Equal(`# Code generated by kusk-gen. DO NOT EDIT.
+namespace: booksapp
+rate_limits:
+  burst: 20
+  rps: 10
+service:
+  name: webapp
+  port: 80
+`, string(values))
+}
+
+func TestNewChartVersion(t *testing.T) {
+	r := require.New(t)
+
+	chart := newChart("/tmp/charts/books/", &openapi3.Info{Title: "Books", Version: "v1"})
+	r.Equal("books", chart.Name)
+	r.Equal("0.1.0", chart.Version)
+	r.Equal("v1", chart.AppVersion)
+}
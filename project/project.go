@@ -0,0 +1,309 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	v "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+// DefaultFileName is the name of the project file kusk-gen build looks for by default
+const DefaultFileName = "kusk.yaml"
+
+// envPlaceholder is replaced with the environment name in output locations
+const envPlaceholder = "{env}"
+
+// Project describes the specs to generate resources for, the generators to run for each of them,
+// and per-environment overrides of the options and output locations.
+type Project struct {
+	// Specs are the OpenAPI specs to generate resources for.
+	Specs []Spec `json:"specs"`
+
+	// Environments are overrides applied to every spec when building an environment.
+	Environments map[string]Environment `json:"environments,omitempty"`
+
+	// dir is the directory of the project file, relative paths are resolved against it
+	dir string
+}
+
+type Spec struct {
+	// Path is the path to the OpenAPI spec, relative to the project file.
+	Path string `json:"path"`
+
+	// OptionsFile is the path to a file with x-kusk options for specs that can't be edited, relative to the project file.
+	OptionsFile string `json:"options_file,omitempty"`
+
+	// Targets are the generators to run for the spec, e.g. linkerd, ambassador2.
+	Targets []string `json:"targets"`
+
+	// Options override the options set in the spec x-kusk extension.
+	// They are kept as written, so that explicit false and zero values override the spec too.
+	Options map[string]interface{} `json:"options,omitempty"`
+
+	// Output tells where to write the generated resources, stdout if not set.
+	Output Output `json:"output,omitempty"`
+
+	// Environments are overrides applied to this spec when building an environment,
+	// they take precedence over the project environments with the same name.
+	Environments map[string]Environment `json:"environments,omitempty"`
+}
+
+type Environment struct {
+	// Options override the spec options when building the environment.
+	Options map[string]interface{} `json:"options,omitempty"`
+
+	// Output replaces the spec output when building the environment.
+	Output Output `json:"output,omitempty"`
+}
+
+// Output is the location to write the generated resources to.
+// Paths are relative to the project file and {env} is replaced with the environment name.
+type Output struct {
+	// Dir to write each resource into its own file.
+	Dir string `json:"dir,omitempty"`
+
+	// Kustomization to also write a kustomization.yaml into Dir.
+	Kustomization bool `json:"kustomization,omitempty"`
+
+	// HelmChart is a directory to package the resources into a Helm chart.
+	HelmChart string `json:"helm_chart,omitempty"`
+
+	// File to write all the resources into.
+	File string `json:"file,omitempty"`
+}
+
+// Build is a spec to generate resources for in a given environment
+type Build struct {
+	SpecPath    string
+	OptionsFile string
+	Targets     []string
+
+	// Env is the environment name, empty when the project doesn't define environments
+	Env string
+
+	// Options override the spec x-kusk extension, in the order of precedence
+	Options []map[string]interface{}
+
+	Output Output
+}
+
+// Load reads and validates the project file
+func Load(path string) (*Project, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project file: %w", err)
+	}
+
+	var res Project
+	if err := yaml.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("failed to parse project file %s: %w", path, err)
+	}
+
+	if err := res.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid project file %s: %w", path, err)
+	}
+
+	res.dir = filepath.Dir(path)
+
+	return &res, nil
+}
+
+func (p *Project) Validate() error {
+	return v.ValidateStruct(p,
+		v.Field(&p.Specs, v.Required.Error("at least one spec is required")),
+		v.Field(&p.Environments),
+	)
+}
+
+func (s Spec) Validate() error {
+	return v.ValidateStruct(&s,
+		v.Field(&s.Path, v.Required.Error("spec path is required")),
+		v.Field(&s.Targets, v.Required.Error("at least one target is required")),
+		v.Field(&s.Options, v.By(validOptions)),
+		v.Field(&s.Output),
+		v.Field(&s.Environments),
+	)
+}
+
+func (e Environment) Validate() error {
+	return v.ValidateStruct(&e,
+		v.Field(&e.Options, v.By(validOptions)),
+		v.Field(&e.Output),
+	)
+}
+
+// validOptions checks that the option overrides decode into options.Options
+func validOptions(value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, &options.Options{})
+}
+
+func (o Output) Validate() error {
+	set := 0
+	for _, location := range []string{o.Dir, o.HelmChart, o.File} {
+		if location != "" {
+			set++
+		}
+	}
+
+	if set > 1 {
+		return fmt.Errorf("only one of dir, helm_chart and file can be set")
+	}
+
+	if o.Kustomization && o.Dir == "" {
+		return fmt.Errorf("kustomization requires dir to be set")
+	}
+
+	return nil
+}
+
+// Builds returns the builds of every spec for the given environment,
+// or for all environments defined in the project if env is empty
+func (p *Project) Builds(env string) ([]Build, error) {
+	envs := p.environmentNames()
+
+	if env != "" {
+		if !contains(envs, env) {
+			return nil, fmt.Errorf("environment %s is not defined in the project", env)
+		}
+
+		envs = []string{env}
+	}
+
+	// build the base options when there are no environments
+	if len(envs) == 0 {
+		envs = []string{""}
+	}
+
+	var res []Build
+	locations := map[string]string{}
+
+	for _, env := range envs {
+		for i := range p.Specs {
+			build := p.build(&p.Specs[i], env)
+
+			// builds writing to the same location would overwrite each other
+			if location := build.location(); location != "" {
+				if other, ok := locations[location]; ok {
+					return nil, fmt.Errorf("%s and %s are both written to %s", other, build, location)
+				}
+
+				locations[location] = build.String()
+			}
+
+			res = append(res, build)
+		}
+	}
+
+	return res, nil
+}
+
+func (p *Project) build(spec *Spec, env string) Build {
+	res := Build{
+		SpecPath:    p.resolve(spec.Path),
+		OptionsFile: p.resolve(spec.OptionsFile),
+		Targets:     spec.Targets,
+		Env:         env,
+		Output:      spec.Output,
+	}
+
+	if len(spec.Options) > 0 {
+		res.Options = append(res.Options, spec.Options)
+	}
+
+	// spec environment takes precedence over the project one
+	for _, environments := range []map[string]Environment{p.Environments, spec.Environments} {
+		environment, ok := environments[env]
+		if !ok {
+			continue
+		}
+
+		if len(environment.Options) > 0 {
+			res.Options = append(res.Options, environment.Options)
+		}
+
+		if environment.Output != (Output{}) {
+			res.Output = environment.Output
+		}
+	}
+
+	res.Output.Dir = p.resolveOutput(res.Output.Dir, env)
+	res.Output.HelmChart = p.resolveOutput(res.Output.HelmChart, env)
+	res.Output.File = p.resolveOutput(res.Output.File, env)
+
+	return res
+}
+
+// environmentNames returns the sorted names of the environments defined in the project and its specs
+func (p *Project) environmentNames() []string {
+	var res []string
+
+	add := func(environments map[string]Environment) {
+		for name := range environments {
+			if !contains(res, name) {
+				res = append(res, name)
+			}
+		}
+	}
+
+	add(p.Environments)
+	for _, spec := range p.Specs {
+		add(spec.Environments)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func (p *Project) resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(p.dir, path)
+}
+
+func (p *Project) resolveOutput(path, env string) string {
+	return p.resolve(strings.ReplaceAll(path, envPlaceholder, env))
+}
+
+// location returns where the build is written to, empty for stdout
+func (b Build) location() string {
+	for _, location := range []string{b.Output.Dir, b.Output.HelmChart, b.Output.File} {
+		if location != "" {
+			return filepath.Clean(location)
+		}
+	}
+
+	return ""
+}
+
+func (b Build) String() string {
+	if b.Env == "" {
+		return b.SpecPath
+	}
+
+	return fmt.Sprintf("%s (%s)", b.SpecPath, b.Env)
+}
+
+func contains(values []string, value string) bool {
+	for _, val := range values {
+		if val == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,273 @@
+package project
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func loadProject(r *require.Assertions, dir, content string) *Project {
+	path := filepath.Join(dir, DefaultFileName)
+
+	r.NoError(ioutil.WriteFile(path, []byte(content), 0644))
+
+	p, err := Load(path)
+	r.NoError(err)
+
+	return p
+}
+
+func TestBuilds(t *testing.T) {
+	testCases := []struct {
+		name    string
+		project string
+		env     string
+		res     func(dir string) []Build
+	}{
+		{
+			name: "no environments",
+			project: `
+specs:
+- path: api/books.yaml
+  targets: [linkerd, ambassador2]
+  options:
+    namespace: books
+  output:
+    dir: deploy/books
+    kustomization: true
+- path: /specs/authors.yaml
+  targets: [istio]
+`,
+			res: func(dir string) []Build {
+				return []Build{
+					{
+						SpecPath: filepath.Join(dir, "api/books.yaml"),
+						Targets:  []string{"linkerd", "ambassador2"},
+						Options:  []map[string]interface{}{{"namespace": "books"}},
+						Output: Output{
+							Dir:           filepath.Join(dir, "deploy/books"),
+							Kustomization: true,
+						},
+					},
+					{
+						SpecPath: "/specs/authors.yaml",
+						Targets:  []string{"istio"},
+					},
+				}
+			},
+		},
+		{
+			name: "environments",
+			project: `
+specs:
+- path: books.yaml
+  targets: [linkerd]
+  output:
+    dir: deploy/{env}/books
+  environments:
+    prod:
+      options:
+        rate_limits:
+          rps: 500
+      output:
+        helm_chart: charts/books
+environments:
+  staging:
+    options:
+      host: books.staging.example.org
+  prod:
+    options:
+      host: books.example.org
+`,
+			res: func(dir string) []Build {
+				return []Build{
+					{
+						SpecPath: filepath.Join(dir, "books.yaml"),
+						Targets:  []string{"linkerd"},
+						Env:      "prod",
+						Options: []map[string]interface{}{
+							{"host": "books.example.org"},
+							{"rate_limits": map[string]interface{}{"rps": float64(500)}},
+						},
+						Output: Output{HelmChart: filepath.Join(dir, "charts/books")},
+					},
+					{
+						SpecPath: filepath.Join(dir, "books.yaml"),
+						Targets:  []string{"linkerd"},
+						Env:      "staging",
+						Options: []map[string]interface{}{
+							{"host": "books.staging.example.org"},
+						},
+						Output: Output{Dir: filepath.Join(dir, "deploy/staging/books")},
+					},
+				}
+			},
+		},
+		{
+			name: "single environment",
+			env:  "staging",
+			project: `
+specs:
+- path: books.yaml
+  targets: [linkerd]
+environments:
+  staging:
+    options:
+      host: books.staging.example.org
+  prod:
+    options:
+      host: books.example.org
+`,
+			res: func(dir string) []Build {
+				return []Build{
+					{
+						SpecPath: filepath.Join(dir, "books.yaml"),
+						Targets:  []string{"linkerd"},
+						Env:      "staging",
+						Options: []map[string]interface{}{
+							{"host": "books.staging.example.org"},
+						},
+					},
+				}
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+			dir := t.TempDir()
+
+			p := loadProject(r, dir, testCase.project)
+
+			builds, err := p.Builds(testCase.env)
+			r.NoError(err)
+			r.Equal(testCase.res(dir), builds)
+		})
+	}
+}
+
+func TestBuildsErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		project string
+		env     string
+		err     string
+	}{
+		{
+			name: "undefined environment",
+			env:  "qa",
+			project: `
+specs:
+- path: books.yaml
+  targets: [linkerd]
+`,
+			err: "environment qa is not defined in the project",
+		},
+		{
+			name: "same output location",
+			project: `
+specs:
+- path: books.yaml
+  targets: [linkerd]
+  output:
+    dir: deploy
+environments:
+  staging: {}
+  prod: {}
+`,
+			err: "are both written to",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+			p := loadProject(r, t.TempDir(), testCase.project)
+
+			_, err := p.Builds(testCase.env)
+			r.Error(err)
+			r.Contains(err.Error(), testCase.err)
+		})
+	}
+}
+
+func TestLoadValidates(t *testing.T) {
+	testCases := []struct {
+		name    string
+		project string
+		err     string
+	}{
+		{
+			name:    "no specs",
+			project: `environments: {}`,
+			err:     "at least one spec is required",
+		},
+		{
+			name: "no targets",
+			project: `
+specs:
+- path: books.yaml
+`,
+			err: "at least one target is required",
+		},
+		{
+			name: "several outputs",
+			project: `
+specs:
+- path: books.yaml
+  targets: [linkerd]
+  output:
+    dir: deploy
+    file: books.yaml
+`,
+			err: "only one of dir, helm_chart and file can be set",
+		},
+		{
+			name: "invalid options",
+			project: `
+specs:
+- path: books.yaml
+  targets: [linkerd]
+  options:
+    path:
+      prefix: "yes"
+`,
+			err: "options:",
+		},
+		{
+			name: "kustomization without dir",
+			project: `
+specs:
+- path: books.yaml
+  targets: [linkerd]
+  environments:
+    prod:
+      output:
+        kustomization: true
+`,
+			err: "kustomization requires dir to be set",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+			path := filepath.Join(t.TempDir(), DefaultFileName)
+
+			r.NoError(ioutil.WriteFile(path, []byte(testCase.project), 0644))
+
+			_, err := Load(path)
+			r.Error(err)
+			r.Contains(err.Error(), testCase.err)
+		})
+	}
+}
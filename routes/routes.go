@@ -0,0 +1,154 @@
+package routes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/kubeshop/kusk-gen/generators/pathparams"
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+var rePathParameter = regexp.MustCompile(`{[^}]*}`)
+
+// Spec is a parsed spec along with its options, Name identifies the spec in the reported conflicts, e.g. its path
+type Spec struct {
+	Name    string
+	Spec    *openapi3.T
+	Options *options.Options
+}
+
+// Route is an enabled operation resolved to the host, full path and method it is served on
+type Route struct {
+	Spec   string
+	Host   string
+	Method string
+
+	// Path is the operation path and FullPath is the path it is served on, including the base path
+	Path     string
+	FullPath string
+
+	// Regex matches the full paths of the requests the route is served on, nil for literal paths
+	// and for paths with parameter patterns that don't compile
+	Regex *regexp.Regexp
+}
+
+func (r Route) String() string {
+	res := fmt.Sprintf("%s %s%s", r.Method, r.Host, r.FullPath)
+	if r.Spec != "" {
+		res += " (" + r.Spec + ")"
+	}
+
+	return res
+}
+
+// Conflict is a pair of routes matching the same requests
+type Conflict struct {
+	Route Route
+	Other Route
+
+	// Shadowed tells that the regex of Route matches the literal path of Other rather than both resolving to the same route,
+	// which proxies matching routes in order or by length can route to Route
+	Shadowed bool
+}
+
+func (c Conflict) String() string {
+	if c.Shadowed {
+		return fmt.Sprintf("%s matches the path of %s and may shadow it", c.Route, c.Other)
+	}
+
+	return fmt.Sprintf("%s and %s resolve to the same route", c.Route, c.Other)
+}
+
+// Resolve returns the enabled operations of the spec resolved to the routes they are served on, sorted by path and method
+func Resolve(spec Spec) []Route {
+	base := strings.TrimSuffix(spec.Options.Path.Base, "/")
+
+	var res []Route
+
+	for path, pathItem := range spec.Spec.Paths {
+		for method, operation := range pathItem.Operations() {
+			if spec.Options.IsOperationDisabled(path, method) {
+				continue
+			}
+
+			route := Route{
+				Spec:     spec.Name,
+				Host:     spec.Options.GetHost(path, method),
+				Method:   method,
+				Path:     path,
+				FullPath: base + path,
+			}
+
+			// the regex is compiled once here rather than for every pair of routes compared,
+			// the static parts of the path, including the base path, are quoted
+			if params := pathparams.Find(pathItem, operation); len(params) > 0 {
+				route.Regex, _ = regexp.Compile("^" + pathparams.Regex(route.FullPath, params, nil) + "$")
+			}
+
+			res = append(res, route)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].FullPath != res[j].FullPath {
+			return res[i].FullPath < res[j].FullPath
+		}
+
+		return res[i].Method < res[j].Method
+	})
+
+	return res
+}
+
+// Find returns the conflicts between the routes of all the specs, both within a spec and across specs.
+// Routes conflict when they have the same method, path and host, routes without a host conflicting with any host.
+// Paths are the same regardless of the names of their parameters, e.g. /users/{id} and /users/{userId}.
+func Find(specs []Spec) []Conflict {
+	var all []Route
+	for _, spec := range specs {
+		all = append(all, Resolve(spec)...)
+	}
+
+	var res []Conflict
+
+	for i, route := range all {
+		for _, other := range all[i+1:] {
+			if route.Method != other.Method || !hostsOverlap(route.Host, other.Host) {
+				continue
+			}
+
+			switch {
+			case normalizePath(route.FullPath) == normalizePath(other.FullPath):
+				res = append(res, Conflict{Route: route, Other: other})
+			case shadows(route, other):
+				res = append(res, Conflict{Route: route, Other: other, Shadowed: true})
+			case shadows(other, route):
+				res = append(res, Conflict{Route: other, Other: route, Shadowed: true})
+			}
+		}
+	}
+
+	return res
+}
+
+func hostsOverlap(host, other string) bool {
+	return host == other || host == "" || other == ""
+}
+
+// normalizePath drops the names of the path parameters
+func normalizePath(path string) string {
+	return rePathParameter.ReplaceAllString(path, "{}")
+}
+
+// shadows tells whether the regex of the route matches the literal path of the other one
+func shadows(route, other Route) bool {
+	if route.Regex == nil || other.Regex != nil {
+		return false
+	}
+
+	return route.Regex.MatchString(other.FullPath)
+}
@@ -0,0 +1,168 @@
+package routes
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk-gen/spec"
+)
+
+func TestFind(t *testing.T) {
+	testCases := []struct {
+		name     string
+		specs    []string
+		expected []string
+	}{
+		{
+			name: "regex shadowing a literal path",
+			specs: []string{`
+openapi: 3.0.2
+info:
+  title: Users
+  version: 1.0.0
+x-kusk:
+  path:
+    base: /v1
+paths:
+  /users/{id}:
+    get:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+  /users/me:
+    get: {}
+    post: {}
+`},
+			expected: []string{
+				"GET /v1/users/{id} matches the path of GET /v1/users/me and may shadow it",
+			},
+		},
+		{
+			name: "same route across specs",
+			specs: []string{`
+openapi: 3.0.2
+info:
+  title: Users
+  version: 1.0.0
+x-kusk:
+  host: api.example.org
+paths:
+  /users/{id}:
+    get: {}
+`, `
+openapi: 3.0.2
+info:
+  title: Accounts
+  version: 1.0.0
+paths:
+  /users/{userId}:
+    get: {}
+`},
+			expected: []string{
+				"GET api.example.org/users/{id} (spec-0) and GET /users/{userId} (spec-1) resolve to the same route",
+			},
+		},
+		{
+			name: "static path segments matched literally",
+			specs: []string{`
+openapi: 3.0.2
+info:
+  title: Users
+  version: 1.0.0
+paths:
+  /v1.0/users/{id}:
+    get:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+  /v1x0/users/me:
+    get: {}
+`},
+		},
+		{
+			name: "different hosts, base paths and disabled operations",
+			specs: []string{`
+openapi: 3.0.2
+info:
+  title: Users
+  version: 1.0.0
+x-kusk:
+  host: users.example.org
+paths:
+  /users:
+    get: {}
+`, `
+openapi: 3.0.2
+info:
+  title: Admin
+  version: 1.0.0
+x-kusk:
+  host: admin.example.org
+paths:
+  /users:
+    get: {}
+`, `
+openapi: 3.0.2
+info:
+  title: Accounts
+  version: 1.0.0
+x-kusk:
+  path:
+    base: /accounts
+paths:
+  /users:
+    get: {}
+`, `
+openapi: 3.0.2
+info:
+  title: Legacy
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      x-kusk:
+        disabled: true
+`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			var specs []Spec
+
+			for i, s := range testCase.specs {
+				apiSpec, err := spec.NewParser(openapi3.NewLoader()).ParseFromReader(strings.NewReader(s))
+				r.NoError(err, "failed to parse spec")
+
+				opts, err := spec.GetOptions(apiSpec)
+				r.NoError(err, "failed to get options")
+
+				name := ""
+				if len(testCase.specs) > 1 {
+					name = fmt.Sprintf("spec-%d", i)
+				}
+
+				specs = append(specs, Spec{Name: name, Spec: apiSpec, Options: opts})
+			}
+
+			var actual []string
+			for _, conflict := range Find(specs) {
+				actual = append(actual, conflict.String())
+			}
+
+			r.Equal(testCase.expected, actual)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package spec
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -10,35 +11,110 @@ import (
 	"github.com/kubeshop/kusk-gen/options"
 )
 
-const kuskExtensionKey = "x-kusk"
+const (
+	kuskExtensionKey = "x-kusk"
 
-func getPathOptions(path *openapi3.PathItem) (options.SubOptions, bool, error) {
+	// environmentsKey holds named profiles of options in x-kusk extension,
+	// merged over the rest of the extension when the environment is selected
+	environmentsKey = "environments"
+)
+
+// ErrEnvironmentNotDefined is returned when the selected environment isn't defined in any x-kusk extension of the spec
+var ErrEnvironmentNotDefined = errors.New("environment is not defined in x-kusk extension")
+
+// extensionParser parses x-kusk extensions for the selected environment
+// and keeps track of whether the environment is defined in any of them
+type extensionParser struct {
+	env        string
+	envDefined bool
+}
+
+func (p *extensionParser) getPathOptions(path *openapi3.PathItem, overlays []json.RawMessage) (options.SubOptions, bool, error) {
+	var res options.SubOptions
+
+	ok, err := p.parseExtension(&path.ExtensionProps, overlays, &res)
+
+	return res, ok, err
+}
+
+func (p *extensionParser) getTagOptions(tag *openapi3.Tag) (options.SubOptions, bool, error) {
 	var res options.SubOptions
 
-	ok, err := parseExtension(&path.ExtensionProps, &res)
+	ok, err := p.parseExtension(&tag.ExtensionProps, nil, &res)
 
 	return res, ok, err
 }
 
-func getOperationOptions(operation *openapi3.Operation) (options.SubOptions, bool, error) {
+func (p *extensionParser) getOperationOptions(operation *openapi3.Operation, overlays []json.RawMessage) (options.SubOptions, bool, error) {
 	var res options.SubOptions
 
-	ok, err := parseExtension(&operation.ExtensionProps, &res)
+	ok, err := p.parseExtension(&operation.ExtensionProps, overlays, &res)
 
 	return res, ok, err
 }
 
 // GetOptions would retrieve and parse x-kusk top-level OpenAPI extension
 // that contains Kusk options. If there's no extension found, an empty object will be returned.
+// Environment profiles are ignored, see GetEnvironmentOptions.
 func GetOptions(spec *openapi3.T) (*options.Options, error) {
+	return GetEnvironmentOptions(spec, "")
+}
+
+// GetEnvironmentOptions is like GetOptions, but merges the options of the given environment profile
+// over the base ones on every level, e.g.
+//
+//	x-kusk:
+//	  host: books.staging.example.org
+//	  environments:
+//	    prod:
+//	      host: books.example.org
+//
+// ErrEnvironmentNotDefined is returned if no x-kusk extension defines the environment.
+// The overlays are merged over the x-kusk extension on every level, in order.
+func GetEnvironmentOptions(spec *openapi3.T, env string, overlays ...*Overlay) (*options.Options, error) {
 	var res options.Options
 
-	if _, err := parseExtension(&spec.ExtensionProps, &res); err != nil {
+	for _, overlay := range overlays {
+		if err := overlay.validate(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &extensionParser{env: env}
+
+	rootOverlays := make([]json.RawMessage, 0, len(overlays))
+	for _, overlay := range overlays {
+		rootOverlays = append(rootOverlays, overlay.root)
+	}
+
+	if _, err := p.parseExtension(&spec.ExtensionProps, rootOverlays, &res); err != nil {
 		return nil, err
 	}
 
+	for _, tag := range spec.Tags {
+		tagSubOptions, ok, err := p.getTagOptions(tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract tag suboptions: %w", err)
+		}
+
+		if ok {
+			if res.TagSubOptions == nil {
+				res.TagSubOptions = map[string]options.SubOptions{}
+			}
+
+			res.TagSubOptions[tag.Name] = tagSubOptions
+		}
+	}
+
 	for path, pathItem := range spec.Paths {
-		pathSubOptions, ok, err := getPathOptions(pathItem)
+		var pathOverlays []json.RawMessage
+		for _, overlay := range overlays {
+			if pathOverlay, ok := overlay.paths[path]; ok {
+				pathOverlays = append(pathOverlays, pathOverlay)
+			}
+		}
+
+		pathSubOptions, ok, err := p.getPathOptions(pathItem, pathOverlays)
 		if err != nil {
 			return nil, fmt.Errorf("failed to extract path suboptions: %w", err)
 		}
@@ -52,7 +128,14 @@ func GetOptions(spec *openapi3.T) (*options.Options, error) {
 		}
 
 		for method, operation := range pathItem.Operations() {
-			operationSubOptions, ok, err := getOperationOptions(operation)
+			var operationOverlays []json.RawMessage
+			for _, overlay := range overlays {
+				if operationOverlay, ok := overlay.operations[method+path]; ok {
+					operationOverlays = append(operationOverlays, operationOverlay)
+				}
+			}
+
+			operationSubOptions, ok, err := p.getOperationOptions(operation, operationOverlays)
 			if err != nil {
 				return nil, fmt.Errorf("failed to extract operation suboptions: %w", err)
 			}
@@ -64,23 +147,145 @@ func GetOptions(spec *openapi3.T) (*options.Options, error) {
 
 				res.OperationSubOptions[method+path] = operationSubOptions
 			}
+
+			if hasTagOptions(operation, res.TagSubOptions) {
+				if res.OperationTags == nil {
+					res.OperationTags = map[string][]string{}
+				}
+
+				res.OperationTags[method+path] = operation.Tags
+			}
 		}
 	}
 
+	if env != "" && !p.envDefined {
+		return nil, fmt.Errorf("%s: %w", env, ErrEnvironmentNotDefined)
+	}
+
 	return &res, nil
 }
 
-func parseExtension(extensionProps *openapi3.ExtensionProps, target interface{}) (bool, error) {
+// hasTagOptions tells whether any of the operation's tags has options
+func hasTagOptions(operation *openapi3.Operation, tagSubOptions map[string]options.SubOptions) bool {
+	for _, tag := range operation.Tags {
+		if _, ok := tagSubOptions[tag]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseExtension parses x-kusk extension merged with the overlays into target,
+// returns false if there is neither extension nor overlays
+func (p *extensionParser) parseExtension(extensionProps *openapi3.ExtensionProps, overlays []json.RawMessage, target interface{}) (bool, error) {
+	var sources []json.RawMessage
+
 	if extension, ok := extensionProps.Extensions[kuskExtensionKey]; ok {
 		if kuskExtension, ok := extension.(json.RawMessage); ok {
-			err := yaml.Unmarshal(kuskExtension, target)
-			if err != nil {
-				return false, fmt.Errorf("failed to parse extension: %w", err)
-			}
+			sources = append(sources, kuskExtension)
+		}
+	}
+
+	sources = append(sources, overlays...)
+
+	if len(sources) == 0 {
+		return false, nil
+	}
+
+	// the bases of all the sources are merged before the environment profiles,
+	// so that an overlay base doesn't override the environment profile of the spec
+	values := map[string]interface{}{}
+	var profiles []map[string]interface{}
+
+	for _, source := range sources {
+		var sourceValues map[string]interface{}
 
-			return true, nil
+		err := yaml.Unmarshal(source, &sourceValues)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse extension: %w", err)
 		}
+
+		profile, err := p.extractEnvironment(sourceValues)
+		if err != nil {
+			return false, err
+		}
+
+		values = mergeValues(values, sourceValues)
+
+		if profile != nil {
+			profiles = append(profiles, profile)
+		}
+	}
+
+	for _, profile := range profiles {
+		values = mergeValues(values, profile)
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse extension: %w", err)
+	}
+
+	if err := yaml.Unmarshal(b, target); err != nil {
+		return false, fmt.Errorf("failed to parse extension: %w", err)
+	}
+
+	return true, nil
+}
+
+// extractEnvironment removes the environment profiles from the extension values
+// and returns the selected one, nil if there's none
+func (p *extensionParser) extractEnvironment(values map[string]interface{}) (map[string]interface{}, error) {
+	environments, ok := values[environmentsKey]
+	if !ok {
+		return nil, nil
+	}
+
+	delete(values, environmentsKey)
+
+	profiles, ok := environments.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to parse extension: %s must be a map of environment names to options", environmentsKey)
+	}
+
+	if p.env == "" {
+		return nil, nil
+	}
+
+	profile, ok := profiles[p.env]
+	if !ok {
+		return nil, nil
+	}
+
+	p.envDefined = true
+
+	// an environment can be defined without overriding anything
+	if profile == nil {
+		return nil, nil
+	}
+
+	profileValues, ok := profile.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to parse extension: %s.%s must be a map of options", environmentsKey, p.env)
+	}
+
+	return profileValues, nil
+}
+
+// mergeValues merges override into base recursively, values other than maps are replaced
+func mergeValues(base, override map[string]interface{}) map[string]interface{} {
+	for key, overrideValue := range override {
+		baseMap, baseIsMap := base[key].(map[string]interface{})
+		overrideMap, overrideIsMap := overrideValue.(map[string]interface{})
+
+		if baseIsMap && overrideIsMap {
+			base[key] = mergeValues(baseMap, overrideMap)
+			continue
+		}
+
+		base[key] = overrideValue
 	}
 
-	return false, nil
+	return base
 }
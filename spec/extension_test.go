@@ -3,6 +3,7 @@ package spec
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/require"
@@ -67,6 +68,38 @@ func TestGetOptions(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "tag level options set",
+			spec: &openapi3.T{
+				Tags: openapi3.Tags{
+					&openapi3.Tag{
+						Name: "admin",
+						ExtensionProps: openapi3.ExtensionProps{
+							Extensions: map[string]interface{}{
+								kuskExtensionKey: json.RawMessage(`{"disabled":true}`),
+							},
+						},
+					},
+					&openapi3.Tag{Name: "pet"},
+				},
+				Paths: openapi3.Paths{
+					"/pet": &openapi3.PathItem{
+						Put: &openapi3.Operation{Tags: []string{"pet", "admin"}},
+						Get: &openapi3.Operation{Tags: []string{"pet"}},
+					},
+				},
+			},
+			res: options.Options{
+				TagSubOptions: map[string]options.SubOptions{
+					"admin": {
+						Disabled: &trueValue,
+					},
+				},
+				OperationTags: map[string][]string{
+					"PUT/pet": {"pet", "admin"},
+				},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -80,3 +113,101 @@ func TestGetOptions(t *testing.T) {
 	}
 
 }
+
+func TestGetEnvironmentOptions(t *testing.T) {
+	trueValue := true
+
+	spec := &openapi3.T{
+		ExtensionProps: openapi3.ExtensionProps{
+			Extensions: map[string]interface{}{
+				kuskExtensionKey: json.RawMessage(`{
+					"host": "books.staging.example.org",
+					"rate_limits": {"rps": 10, "burst": 20},
+					"environments": {
+						"prod": {"host": "books.example.org", "rate_limits": {"rps": 100}},
+						"dev": null
+					}
+				}`),
+			},
+		},
+		Paths: openapi3.Paths{
+			"/internal": &openapi3.PathItem{
+				ExtensionProps: openapi3.ExtensionProps{
+					Extensions: map[string]interface{}{
+						kuskExtensionKey: json.RawMessage(`{"environments": {"prod": {"disabled": true}}}`),
+					},
+				},
+				Get: &openapi3.Operation{
+					ExtensionProps: openapi3.ExtensionProps{
+						Extensions: map[string]interface{}{
+							kuskExtensionKey: json.RawMessage(`{"timeouts": {"request_timeout": 5}, "environments": {"prod": {"timeouts": {"idle_timeout": 10}}}}`),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var testCases = []struct {
+		name string
+		env  string
+		res  options.Options
+	}{
+		{
+			name: "no environment",
+			res: options.Options{
+				Host:       "books.staging.example.org",
+				RateLimits: options.RateLimitOptions{RPS: 10, Burst: 20},
+				PathSubOptions: map[string]options.SubOptions{
+					"/internal": {},
+				},
+				OperationSubOptions: map[string]options.SubOptions{
+					"GET/internal": {Timeouts: options.TimeoutOptions{RequestTimeout: options.Duration(5 * time.Second)}},
+				},
+			},
+		},
+		{
+			name: "environment merged over base options",
+			env:  "prod",
+			res: options.Options{
+				Host:       "books.example.org",
+				RateLimits: options.RateLimitOptions{RPS: 100, Burst: 20},
+				PathSubOptions: map[string]options.SubOptions{
+					"/internal": {Disabled: &trueValue},
+				},
+				OperationSubOptions: map[string]options.SubOptions{
+					"GET/internal": {Timeouts: options.TimeoutOptions{RequestTimeout: options.Duration(5 * time.Second), IdleTimeout: options.Duration(10 * time.Second)}},
+				},
+			},
+		},
+		{
+			name: "environment without overrides",
+			env:  "dev",
+			res: options.Options{
+				Host:       "books.staging.example.org",
+				RateLimits: options.RateLimitOptions{RPS: 10, Burst: 20},
+				PathSubOptions: map[string]options.SubOptions{
+					"/internal": {},
+				},
+				OperationSubOptions: map[string]options.SubOptions{
+					"GET/internal": {Timeouts: options.TimeoutOptions{RequestTimeout: options.Duration(5 * time.Second)}},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			actual, err := GetEnvironmentOptions(spec, testCase.env)
+			r.NoError(err, "failed to get options")
+			r.Equal(testCase.res, *actual)
+		})
+	}
+
+	t.Run("undefined environment", func(t *testing.T) {
+		_, err := GetEnvironmentOptions(spec, "qa")
+		require.ErrorIs(t, err, ErrEnvironmentNotDefined)
+	})
+}
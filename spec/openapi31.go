@@ -0,0 +1,256 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+const componentsPathItemsRef = "#/components/pathItems/"
+
+// literalKeys hold values rather than schemas and are left untouched, along with the extensions
+var literalKeys = map[string]bool{
+	"example":  true,
+	"examples": true,
+	"default":  true,
+	"enum":     true,
+}
+
+// namedKeys hold maps keyed by names, e.g. property names, rather than keywords
+var namedKeys = map[string]bool{
+	"properties":        true,
+	"patternProperties": true,
+	"$defs":             true,
+	"definitions":       true,
+	"schemas":           true,
+	"parameters":        true,
+	"responses":         true,
+	"requestBodies":     true,
+	"headers":           true,
+	"securitySchemes":   true,
+	"links":             true,
+	"callbacks":         true,
+	"pathItems":         true,
+	"paths":             true,
+	"webhooks":          true,
+	"content":           true,
+	"encoding":          true,
+	"variables":         true,
+}
+
+// downgradeOpenAPI31 rewrites an OpenAPI 3.1 spec into its OpenAPI 3.0 equivalent, so that it can be loaded:
+//   - path items referenced from components/pathItems are inlined into paths
+//   - JSON Schema 2020-12 keywords are rewritten, see downgradeSchemas
+//
+// Webhooks are kept as they are, they are requests sent by the service rather than routes to it.
+func downgradeOpenAPI31(spec []byte) ([]byte, error) {
+	value, err := decodeValue(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("spec must be an object")
+	}
+
+	if err := inlinePathItems(root); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(downgradeValue(root, false))
+}
+
+// downgradeSchemas rewrites JSON Schema 2020-12 keywords of OpenAPI 3.1 into their OpenAPI 3.0 equivalents:
+//   - type lists, e.g. [string, "null"], become a single type, nullable, or anyOf of the types
+//   - numeric exclusiveMinimum and exclusiveMaximum become minimum and maximum with the boolean flag set
+//   - const becomes a single value enum
+//
+// The rest of the keywords are left as they are. Referenced files don't tell their OpenAPI version,
+// which is fine as these constructs are not valid in OpenAPI 3.0 anyway.
+func downgradeSchemas(data []byte) ([]byte, error) {
+	value, err := decodeValue(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(downgradeValue(value, false))
+}
+
+// decodeValue decodes YAML or JSON keeping the numbers as they are written
+func decodeValue(data []byte) (interface{}, error) {
+	b, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML to JSON: %w", err)
+	}
+
+	var res interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// inlinePathItems replaces references to components/pathItems in paths with the referenced path items,
+// the fields next to the reference take precedence
+func inlinePathItems(root map[string]interface{}) error {
+	paths, ok := root["paths"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	components, _ := root["components"].(map[string]interface{})
+	pathItems, _ := components["pathItems"].(map[string]interface{})
+
+	for path, value := range paths {
+		pathItem, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// referenced path items can refer to other ones in turn
+		for seen := map[string]bool{}; ; {
+			ref, ok := pathItem["$ref"].(string)
+			if !ok || !strings.HasPrefix(ref, componentsPathItemsRef) {
+				break
+			}
+
+			if seen[ref] {
+				return fmt.Errorf("circular path item reference %s in %s", ref, path)
+			}
+
+			seen[ref] = true
+
+			name := strings.NewReplacer("~1", "/", "~0", "~").Replace(strings.TrimPrefix(ref, componentsPathItemsRef))
+
+			referenced, ok := pathItems[name].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("path item %s referenced in %s is not defined", ref, path)
+			}
+
+			inlined := make(map[string]interface{}, len(referenced)+len(pathItem))
+			for key, value := range referenced {
+				inlined[key] = value
+			}
+
+			for key, value := range pathItem {
+				if key != "$ref" {
+					inlined[key] = value
+				}
+			}
+
+			pathItem = inlined
+		}
+
+		paths[path] = pathItem
+	}
+
+	return nil
+}
+
+// downgradeValue rewrites the schema keywords found in value, named tells whether value is keyed by names, e.g. properties
+func downgradeValue(value interface{}, named bool) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, v := range value {
+			if !named && (literalKeys[key] || strings.HasPrefix(key, "x-")) {
+				continue
+			}
+
+			value[key] = downgradeValue(v, !named && namedKeys[key])
+		}
+
+		if !named {
+			downgradeKeywords(value)
+		}
+	case []interface{}:
+		for i, v := range value {
+			value[i] = downgradeValue(v, false)
+		}
+	}
+
+	return value
+}
+
+// downgradeKeywords rewrites the JSON Schema 2020-12 keywords of the schema
+func downgradeKeywords(schema map[string]interface{}) {
+	if types, ok := schema["type"].([]interface{}); ok {
+		var anyOf []interface{}
+
+		for _, t := range types {
+			if t == "null" {
+				schema["nullable"] = true
+				continue
+			}
+
+			anyOf = append(anyOf, map[string]interface{}{"type": t})
+		}
+
+		delete(schema, "type")
+
+		switch {
+		case len(anyOf) == 1:
+			schema["type"] = anyOf[0].(map[string]interface{})["type"]
+		case len(anyOf) > 1:
+			if _, ok := schema["anyOf"]; ok {
+				allOf, _ := schema["allOf"].([]interface{})
+				schema["allOf"] = append(allOf, map[string]interface{}{"anyOf": anyOf})
+			} else {
+				schema["anyOf"] = anyOf
+			}
+		}
+	}
+
+	for exclusive, inclusive := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		bound, ok := schema[exclusive].(json.Number)
+		if !ok {
+			continue
+		}
+
+		schema[exclusive] = true
+
+		// both bounds can be set in OpenAPI 3.1, keep the stricter one
+		if current, ok := schema[inclusive].(json.Number); ok && isStricter(inclusive, current, bound) {
+			delete(schema, exclusive)
+			continue
+		}
+
+		schema[inclusive] = bound
+	}
+
+	if value, ok := schema["const"]; ok {
+		if _, ok := schema["enum"]; !ok {
+			schema["enum"] = []interface{}{value}
+		}
+
+		delete(schema, "const")
+	}
+}
+
+// isStricter tells whether the inclusive bound is stricter than the exclusive one
+func isStricter(inclusive string, bound, exclusiveBound json.Number) bool {
+	b, err := bound.Float64()
+	if err != nil {
+		return false
+	}
+
+	exclusive, err := exclusiveBound.Float64()
+	if err != nil {
+		return false
+	}
+
+	if inclusive == "minimum" {
+		return b > exclusive
+	}
+
+	return b < exclusive
+}
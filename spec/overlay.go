@@ -0,0 +1,206 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+)
+
+// overlayPathsKey holds path and operation level options in an overlay
+const overlayPathsKey = "paths"
+
+var overlayMethods = []string{"connect", "delete", "get", "head", "options", "patch", "post", "put", "trace"}
+
+// Overlay is x-kusk extension kept apart from the spec, for specs that can't be edited,
+// e.g. generated from code or owned by another team. It has the shape of the root x-kusk extension
+// with an additional paths property keyed by path, holding the path level options and the operation level ones keyed by method:
+//
+//	host: books.example.org
+//	paths:
+//	  /internal:
+//	    disabled: true
+//	  /books:
+//	    get:
+//	      timeouts:
+//	        request_timeout: 5
+//
+// Overlay options take precedence over the ones in the spec on the same level.
+type Overlay struct {
+	root json.RawMessage
+
+	// paths is keyed by path
+	paths map[string]json.RawMessage
+
+	// operations is keyed by method+path, like options.Options.OperationSubOptions
+	operations map[string]json.RawMessage
+
+	// file the overlay was loaded from, empty if parsed
+	file string
+}
+
+// LoadOverlay reads the overlay from a YAML or JSON file
+func LoadOverlay(path string) (*Overlay, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read options file: %w", err)
+	}
+
+	res, err := ParseOverlay(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse options file %s: %w", path, err)
+	}
+
+	res.file = path
+
+	return res, nil
+}
+
+// ParseOverlay parses the overlay from YAML or JSON
+func ParseOverlay(b []byte) (*Overlay, error) {
+	var values map[string]json.RawMessage
+	if err := yaml.Unmarshal(b, &values); err != nil {
+		return nil, err
+	}
+
+	res := &Overlay{
+		paths:      map[string]json.RawMessage{},
+		operations: map[string]json.RawMessage{},
+	}
+
+	var paths map[string]map[string]json.RawMessage
+
+	if rawPaths, ok := values[overlayPathsKey]; ok {
+		if err := json.Unmarshal(rawPaths, &paths); err != nil {
+			return nil, fmt.Errorf("%s must be a map of paths to options: %w", overlayPathsKey, err)
+		}
+
+		delete(values, overlayPathsKey)
+	}
+
+	root, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	res.root = root
+
+	for path, pathValues := range paths {
+		for key, value := range pathValues {
+			if !isOverlayMethod(key) {
+				continue
+			}
+
+			res.operations[strings.ToUpper(key)+path] = value
+			delete(pathValues, key)
+		}
+
+		// paths with operation level options only don't get path level ones
+		if len(pathValues) == 0 {
+			continue
+		}
+
+		pathOptions, err := json.Marshal(pathValues)
+		if err != nil {
+			return nil, err
+		}
+
+		res.paths[path] = pathOptions
+	}
+
+	return res, nil
+}
+
+// validate checks that the paths and operations of the overlay exist in the spec
+func (o *Overlay) validate(spec *openapi3.T) error {
+	unknown := map[string]bool{}
+
+	for path := range o.paths {
+		if _, ok := spec.Paths[path]; !ok {
+			unknown[path] = true
+		}
+	}
+
+	for key := range o.operations {
+		i := strings.Index(key, "/")
+		if i < 0 {
+			unknown[key] = true
+			continue
+		}
+
+		method, path := key[:i], key[i:]
+
+		pathItem, ok := spec.Paths[path]
+		if !ok {
+			unknown[path] = true
+			continue
+		}
+
+		if pathItem.GetOperation(method) == nil {
+			unknown[strings.ToLower(method)+" "+path] = true
+		}
+	}
+
+	if len(unknown) > 0 {
+		var res []string
+		for key := range unknown {
+			res = append(res, key)
+		}
+
+		sort.Strings(res)
+
+		return fmt.Errorf("options file refers to paths or operations not in the spec: %s", strings.Join(res, ", "))
+	}
+
+	return nil
+}
+
+// validateSchema checks the options of the overlay against the JSON Schema of x-kusk extension,
+// the errors point to the options in the overlay
+func (o *Overlay) validateSchema() (ExtensionErrors, error) {
+	res, err := validateRaw("", o.root, rootSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	for path, raw := range o.paths {
+		errs, err := validateRaw("/"+overlayPathsKey+"/"+escapePointer(path), raw, subOptionsSchema)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, errs...)
+	}
+
+	for key, raw := range o.operations {
+		i := strings.Index(key, "/")
+		method, path := key[:i], key[i:]
+
+		errs, err := validateRaw("/"+overlayPathsKey+"/"+escapePointer(path)+"/"+strings.ToLower(method), raw, subOptionsSchema)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, errs...)
+	}
+
+	for i := range res {
+		res[i].File = o.file
+	}
+
+	return res, nil
+}
+
+func isOverlayMethod(key string) bool {
+	for _, method := range overlayMethods {
+		if strings.ToLower(key) == method {
+			return true
+		}
+	}
+
+	return false
+}
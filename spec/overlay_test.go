@@ -0,0 +1,117 @@
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+func TestGetEnvironmentOptionsWithOverlay(t *testing.T) {
+	trueValue := true
+
+	spec := &openapi3.T{
+		ExtensionProps: openapi3.ExtensionProps{
+			Extensions: map[string]interface{}{
+				kuskExtensionKey: json.RawMessage(`{"host": "books.example.org", "rate_limits": {"rps": 10, "burst": 20}, "environments": {"prod": {"rate_limits": {"rps": 50}}}}`),
+			},
+		},
+		Paths: openapi3.Paths{
+			"/internal": &openapi3.PathItem{
+				Get: &openapi3.Operation{},
+			},
+			"/books": &openapi3.PathItem{
+				ExtensionProps: openapi3.ExtensionProps{
+					Extensions: map[string]interface{}{
+						kuskExtensionKey: json.RawMessage(`{"timeouts": {"request_timeout": 5}}`),
+					},
+				},
+				Get: &openapi3.Operation{},
+			},
+		},
+	}
+
+	overlay, err := ParseOverlay([]byte(`
+rate_limits:
+  rps: 100
+environments:
+  prod:
+    host: books.prod.example.org
+paths:
+  /internal:
+    disabled: true
+  /books:
+    timeouts:
+      idle_timeout: 10
+    get:
+      host: books.internal
+`))
+	require.NoError(t, err)
+
+	var testCases = []struct {
+		name string
+		env  string
+		res  options.Options
+	}{
+		{
+			name: "overlay merged over the extension",
+			res: options.Options{
+				Host:       "books.example.org",
+				RateLimits: options.RateLimitOptions{RPS: 100, Burst: 20},
+				PathSubOptions: map[string]options.SubOptions{
+					"/internal": {Disabled: &trueValue},
+					"/books":    {Timeouts: options.TimeoutOptions{RequestTimeout: options.Duration(5 * time.Second), IdleTimeout: options.Duration(10 * time.Second)}},
+				},
+				OperationSubOptions: map[string]options.SubOptions{
+					"GET/books": {Host: "books.internal"},
+				},
+			},
+		},
+		{
+			// the environment profile of the spec is merged over the base options of the overlay
+			name: "overlay environment",
+			env:  "prod",
+			res: options.Options{
+				Host:       "books.prod.example.org",
+				RateLimits: options.RateLimitOptions{RPS: 50, Burst: 20},
+				PathSubOptions: map[string]options.SubOptions{
+					"/internal": {Disabled: &trueValue},
+					"/books":    {Timeouts: options.TimeoutOptions{RequestTimeout: options.Duration(5 * time.Second), IdleTimeout: options.Duration(10 * time.Second)}},
+				},
+				OperationSubOptions: map[string]options.SubOptions{
+					"GET/books": {Host: "books.internal"},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			actual, err := GetEnvironmentOptions(spec, testCase.env, overlay)
+			r.NoError(err, "failed to get options")
+			r.Equal(testCase.res, *actual)
+		})
+	}
+
+	t.Run("unknown paths and operations", func(t *testing.T) {
+		r := require.New(t)
+
+		overlay, err := ParseOverlay([]byte(`
+paths:
+  /authors:
+    get: {}
+  /books:
+    post: {}
+`))
+		r.NoError(err)
+
+		_, err = GetEnvironmentOptions(spec, "", overlay)
+		r.EqualError(err, "options file refers to paths or operations not in the spec: /authors, post /books")
+	})
+}
@@ -0,0 +1,287 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+var (
+	// rootSchema describes the root x-kusk extension
+	rootSchema = extensionSchema(reflect.TypeOf(options.Options{}))
+
+	// subOptionsSchema describes the path and operation level x-kusk extension
+	subOptionsSchema = extensionSchema(reflect.TypeOf(options.SubOptions{}))
+
+	// constraints narrow down the values allowed by the option types, keyed by dot-separated option path
+	constraints = map[string]func(schema *openapi3.Schema){
+		"service.port": func(schema *openapi3.Schema) {
+			schema.WithMin(1).WithMax(65535)
+		},
+		"envoy.format": func(schema *openapi3.Schema) {
+			schema.WithEnum("yaml", "json")
+		},
+	}
+
+	// durationSchema describes timeouts given as a number of seconds or as a duration string, e.g. 250ms or 1h30m
+	durationSchema = openapi3.NewOneOfSchema(
+		openapi3.NewFloat64Schema().WithMin(0),
+		openapi3.NewStringSchema().WithPattern(`^(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|ms|s|m|h))+$`),
+	)
+
+	unsupportedPropertyReason = regexp.MustCompile(`^property "(.*)" is unsupported$`)
+)
+
+// ExtensionError is a problem found in x-kusk extension
+type ExtensionError struct {
+	// File is the options file the error was found in, empty for the spec
+	File string
+
+	// Pointer is the JSON pointer of the offending node in the spec or options file,
+	// e.g. /paths/~1books/get/x-kusk/timeouts/request_timeout
+	Pointer string
+
+	Reason string
+}
+
+func (e ExtensionError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%s#%s: %s", e.File, e.Pointer, e.Reason)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Pointer, e.Reason)
+}
+
+// ExtensionErrors are the problems found in x-kusk extension, sorted by pointer
+type ExtensionErrors []ExtensionError
+
+func (e ExtensionErrors) Error() string {
+	res := make([]string, 0, len(e))
+	for _, err := range e {
+		res = append(res, err.Error())
+	}
+
+	return strings.Join(res, "\n")
+}
+
+// JSONSchema returns the JSON Schema of the root x-kusk extension,
+// the path and operation level extension is described in its sub_options definition
+func JSONSchema() ([]byte, error) {
+	var res map[string]interface{}
+
+	b, err := json.Marshal(rootSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+
+	res["$schema"] = "http://json-schema.org/draft-07/schema#"
+	res["title"] = "x-kusk"
+	res["description"] = "kusk-gen OpenAPI extension at the root of the spec, " +
+		"see definitions/sub_options for the extension at the path and operation level"
+	res["definitions"] = map[string]interface{}{
+		"sub_options": subOptionsSchema,
+	}
+
+	return json.MarshalIndent(res, "", "  ")
+}
+
+// ValidateExtension checks x-kusk extension on every level of the spec and the overlays against the JSON Schema,
+// reporting unknown options, values of wrong types and invalid values as ExtensionErrors
+func ValidateExtension(spec *openapi3.T, overlays ...*Overlay) error {
+	var res ExtensionErrors
+
+	validate := func(pointer string, extensionProps *openapi3.ExtensionProps, schema *openapi3.Schema) error {
+		extension, ok := extensionProps.Extensions[kuskExtensionKey].(json.RawMessage)
+		if !ok {
+			return nil
+		}
+
+		errs, err := validateRaw(pointer+"/"+kuskExtensionKey, extension, schema)
+		res = append(res, errs...)
+
+		return err
+	}
+
+	if err := validate("", &spec.ExtensionProps, rootSchema); err != nil {
+		return err
+	}
+
+	for i, tag := range spec.Tags {
+		if err := validate(fmt.Sprintf("/tags/%d", i), &tag.ExtensionProps, subOptionsSchema); err != nil {
+			return err
+		}
+	}
+
+	for path, pathItem := range spec.Paths {
+		pathPointer := "/paths/" + escapePointer(path)
+
+		if err := validate(pathPointer, &pathItem.ExtensionProps, subOptionsSchema); err != nil {
+			return err
+		}
+
+		for method, operation := range pathItem.Operations() {
+			if err := validate(pathPointer+"/"+strings.ToLower(method), &operation.ExtensionProps, subOptionsSchema); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, overlay := range overlays {
+		errs, err := overlay.validateSchema()
+		if err != nil {
+			return err
+		}
+
+		res = append(res, errs...)
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].File != res[j].File {
+			return res[i].File < res[j].File
+		}
+
+		if res[i].Pointer != res[j].Pointer {
+			return res[i].Pointer < res[j].Pointer
+		}
+
+		return res[i].Reason < res[j].Reason
+	})
+
+	return res
+}
+
+// validateRaw validates YAML or JSON raw value against the schema, reporting the errors relative to pointer
+func validateRaw(pointer string, raw []byte, schema *openapi3.Schema) (ExtensionErrors, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("failed to parse extension at %s: %w", pointer, err)
+	}
+
+	// empty extension
+	if value == nil {
+		return nil, nil
+	}
+
+	err := schema.VisitJSON(value, openapi3.MultiErrors())
+	if err == nil {
+		return nil, nil
+	}
+
+	return schemaErrors(pointer, err), nil
+}
+
+func schemaErrors(pointer string, err error) ExtensionErrors {
+	var res ExtensionErrors
+
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		for _, e := range err {
+			res = append(res, schemaErrors(pointer, e)...)
+		}
+	case *openapi3.SchemaError:
+		errPointer := pointer
+		for _, key := range err.JSONPointer() {
+			errPointer += "/" + escapePointer(key)
+		}
+
+		reason := err.Reason
+
+		// oneOf mismatches come without a reason
+		if err.Schema == durationSchema {
+			reason = "must be a non-negative number of seconds or a duration, e.g. 250ms"
+		}
+
+		// point to the unknown property itself rather than to the object containing it
+		if match := unsupportedPropertyReason.FindStringSubmatch(reason); match != nil {
+			errPointer += "/" + escapePointer(match[1])
+			reason = "unknown option"
+		}
+
+		res = append(res, ExtensionError{Pointer: errPointer, Reason: reason})
+	default:
+		res = append(res, ExtensionError{Pointer: pointer, Reason: err.Error()})
+	}
+
+	return res
+}
+
+// extensionSchema returns the schema of x-kusk extension decoded into the type t,
+// allowing environment profiles of the same options
+func extensionSchema(t reflect.Type) *openapi3.Schema {
+	res := typeSchema(t, "")
+	res.WithProperty(environmentsKey, openapi3.NewObjectSchema().WithAdditionalProperties(typeSchema(t, "")))
+
+	return res
+}
+
+// typeSchema returns the schema of values decoded into the type t, path is the dot-separated path of the option
+func typeSchema(t reflect.Type, path string) *openapi3.Schema {
+	var res *openapi3.Schema
+
+	if t == reflect.TypeOf(options.Duration(0)) {
+		return durationSchema
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem(), path)
+	case reflect.Struct:
+		res = openapi3.NewObjectSchema()
+		res.AdditionalPropertiesAllowed = openapi3.BoolPtr(false)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" || name == "" {
+				continue
+			}
+
+			res.WithProperty(name, typeSchema(field.Type, strings.TrimPrefix(path+"."+name, ".")))
+		}
+	case reflect.Map:
+		res = openapi3.NewObjectSchema().WithAdditionalProperties(typeSchema(t.Elem(), path))
+	case reflect.Slice:
+		res = openapi3.NewArraySchema().WithItems(typeSchema(t.Elem(), path))
+	case reflect.Bool:
+		res = openapi3.NewBoolSchema()
+	case reflect.String:
+		res = openapi3.NewStringSchema()
+	case reflect.Int32:
+		res = openapi3.NewInt32Schema()
+	case reflect.Uint32:
+		res = openapi3.NewIntegerSchema().WithMin(0).WithMax(math.MaxUint32)
+	case reflect.Int, reflect.Int64:
+		res = openapi3.NewIntegerSchema()
+	default:
+		panic(fmt.Sprintf("no schema for option %s of type %s", path, t))
+	}
+
+	if constrain, ok := constraints[path]; ok {
+		constrain(res)
+	}
+
+	return res
+}
+
+// escapePointer escapes a JSON pointer reference token
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
@@ -0,0 +1,170 @@
+package spec
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateExtension(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		spec    *openapi3.T
+		overlay string
+		errs    ExtensionErrors
+	}{
+		{
+			name: "valid extension",
+			spec: &openapi3.T{
+				ExtensionProps: openapi3.ExtensionProps{
+					Extensions: map[string]interface{}{
+						kuskExtensionKey: json.RawMessage(`{"host": "books.example.org", "service": {"port": 8080}, "environments": {"prod": {"rate_limits": {"rps": 100}}}}`),
+					},
+				},
+				Paths: openapi3.Paths{
+					"/books": &openapi3.PathItem{
+						ExtensionProps: openapi3.ExtensionProps{
+							Extensions: map[string]interface{}{
+								kuskExtensionKey: json.RawMessage(`{"timeouts": {"request_timeout": 5, "idle_timeout": "1m30s"}}`),
+							},
+						},
+						Get: &openapi3.Operation{
+							ExtensionProps: openapi3.ExtensionProps{
+								Extensions: map[string]interface{}{
+									kuskExtensionKey: json.RawMessage(`{"disabled": true, "cors": {"origins": ["*"]}}`),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unknown options",
+			spec: &openapi3.T{
+				ExtensionProps: openapi3.ExtensionProps{
+					Extensions: map[string]interface{}{
+						kuskExtensionKey: json.RawMessage(`{"rate_limit": {"rps": 100}, "environments": {"prod": {"timeout": 5}}}`),
+					},
+				},
+				Tags: openapi3.Tags{
+					&openapi3.Tag{Name: "books"},
+					&openapi3.Tag{
+						Name: "admin",
+						ExtensionProps: openapi3.ExtensionProps{
+							Extensions: map[string]interface{}{
+								kuskExtensionKey: json.RawMessage(`{"disable": true}`),
+							},
+						},
+					},
+				},
+				Paths: openapi3.Paths{
+					"/books/{id}": &openapi3.PathItem{
+						Get: &openapi3.Operation{
+							ExtensionProps: openapi3.ExtensionProps{
+								Extensions: map[string]interface{}{
+									kuskExtensionKey: json.RawMessage(`{"namespace": "books", "timeouts": {"request": 5}}`),
+								},
+							},
+						},
+					},
+				},
+			},
+			errs: ExtensionErrors{
+				{Pointer: "/paths/~1books~1{id}/get/x-kusk/namespace", Reason: "unknown option"},
+				{Pointer: "/paths/~1books~1{id}/get/x-kusk/timeouts/request", Reason: "unknown option"},
+				{Pointer: "/tags/1/x-kusk/disable", Reason: "unknown option"},
+				{Pointer: "/x-kusk/environments/prod/timeout", Reason: "unknown option"},
+				{Pointer: "/x-kusk/rate_limit", Reason: "unknown option"},
+			},
+		},
+		{
+			name: "wrong types and invalid values",
+			spec: &openapi3.T{
+				ExtensionProps: openapi3.ExtensionProps{
+					Extensions: map[string]interface{}{
+						kuskExtensionKey: json.RawMessage(`{"cors": {"origins": "*"}, "service": {"port": 70000}, "envoy": {"format": "toml"}}`),
+					},
+				},
+				Paths: openapi3.Paths{
+					"/books": &openapi3.PathItem{
+						ExtensionProps: openapi3.ExtensionProps{
+							Extensions: map[string]interface{}{
+								kuskExtensionKey: json.RawMessage(`{"disabled": "yes"}`),
+							},
+						},
+					},
+				},
+			},
+			errs: ExtensionErrors{
+				{Pointer: "/paths/~1books/x-kusk/disabled", Reason: "Field must be set to boolean or not be present"},
+				{Pointer: "/x-kusk/cors/origins", Reason: "Field must be set to array or not be present"},
+				{Pointer: "/x-kusk/envoy/format", Reason: "value is not one of the allowed values"},
+				{Pointer: "/x-kusk/service/port", Reason: "number must be most 65535"},
+			},
+		},
+		{
+			name: "options file",
+			spec: &openapi3.T{
+				Paths: openapi3.Paths{
+					"/books": &openapi3.PathItem{
+						Get: &openapi3.Operation{},
+					},
+				},
+			},
+			overlay: `
+hosts: books.example.org
+paths:
+  /books:
+    timeouts:
+      request_timeout: 5 seconds
+    get:
+      rate_limits:
+        rsp: 100
+`,
+			errs: ExtensionErrors{
+				{Pointer: "/hosts", Reason: "unknown option"},
+				{Pointer: "/paths/~1books/get/rate_limits/rsp", Reason: "unknown option"},
+				{Pointer: "/paths/~1books/timeouts/request_timeout", Reason: "must be a non-negative number of seconds or a duration, e.g. 250ms"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			var overlays []*Overlay
+
+			if testCase.overlay != "" {
+				overlay, err := ParseOverlay([]byte(testCase.overlay))
+				r.NoError(err)
+
+				overlays = append(overlays, overlay)
+			}
+
+			err := ValidateExtension(testCase.spec, overlays...)
+			if testCase.errs == nil {
+				r.NoError(err)
+				return
+			}
+
+			r.Equal(testCase.errs, err)
+		})
+	}
+}
+
+func TestJSONSchemaIsPublished(t *testing.T) {
+	r := require.New(t)
+
+	expected, err := JSONSchema()
+	r.NoError(err)
+
+	published, err := ioutil.ReadFile("../docs/x-kusk.schema.json")
+	r.NoError(err)
+
+	r.JSONEq(string(expected), string(published), "docs/x-kusk.schema.json is outdated, regenerate it with kusk-gen schema")
+}
@@ -1,11 +1,14 @@
 package spec
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi2conv"
@@ -13,79 +16,155 @@ import (
 	"github.com/ghodss/yaml"
 )
 
-// isSwagger tries to decode the spec header
-func isSwagger(spec []byte) bool {
-	// internal helper struct to help us differentiate
-	// between openapi spec 2.0 (swagger) and openapi 3+
-	var header struct {
-		Swagger string `json:"swagger"`
-		OpenAPI string `json:"openapi"` // we might need that later to distinguish 3.1.x vs 3.0.x
-	}
+// specHeader is an internal helper struct to help us differentiate
+// between openapi spec 2.0 (swagger), 3.0.x and 3.1.x
+type specHeader struct {
+	Swagger string `json:"swagger"`
+	OpenAPI string `json:"openapi"`
+}
+
+// decodeHeader tries to decode the spec header
+func decodeHeader(spec []byte) specHeader {
+	var header specHeader
 
 	_ = yaml.Unmarshal(spec, &header)
 
-	return header.Swagger != ""
+	return header
 }
 
+func isSwagger(spec []byte) bool {
+	return decodeHeader(spec).Swagger != ""
+}
+
+func isOpenAPI31(spec []byte) bool {
+	return strings.HasPrefix(decodeHeader(spec).OpenAPI, "3.1.")
+}
+
+// StdinPath is the spec path to read the spec from stdin
+const StdinPath = "-"
+
+// Loader loads OpenAPI 3 specs, resolving references to other files and URLs relative to the location of the spec
 type Loader interface {
-	LoadFromURI(location *url.URL) (*openapi3.T, error)
-	LoadFromFile(location string) (*openapi3.T, error)
+	LoadFromDataWithPath(data []byte, location *url.URL) (*openapi3.T, error)
+}
+
+// NewLoader returns a loader allowing references to other files and URLs, e.g. components kept in a separate file.
+// The schemas in the referenced files are downgraded from OpenAPI 3.1, see downgradeSchemas.
+func NewLoader() *openapi3.Loader {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(_ *openapi3.Loader, location *url.URL) ([]byte, error) {
+		data, err := readFromURI(location)
+		if err != nil {
+			return nil, err
+		}
+
+		return downgradeSchemas(data)
+	}
+
+	return loader
 }
 
 type Parser struct {
 	loader Loader
+
+	// readFromURI reads the spec from a local file path or a remote URL
+	readFromURI func(location *url.URL) ([]byte, error)
+
+	stdin io.Reader
 }
 
 func NewParser(loader Loader) Parser {
 	return Parser{
-		loader: loader,
+		loader:      loader,
+		readFromURI: readFromURI,
+		stdin:       os.Stdin,
 	}
 }
 
 // Parse is the entrypoint for the spec package
 // Accepts a path that should be parseable into a resource locater
-// i.e. a URL or relative file path
+// i.e. a URL or relative file path, or StdinPath to read the spec from stdin.
+// References to other files are resolved relative to the spec location.
 func (p Parser) Parse(path string) (*openapi3.T, error) {
-	u, err := url.Parse(path)
+	if path == StdinPath {
+		return p.ParseFromReader(p.stdin)
+	}
+
+	location, err := url.Parse(path)
 	if err != nil {
 		return nil, fmt.Errorf("invalid resource path %s: %w", path, err)
 	}
 
-	var spec *openapi3.T
-	if isURLRelative := u.Host == ""; isURLRelative {
-		spec, err = p.loader.LoadFromFile(path)
-	} else {
-		spec, err = p.loader.LoadFromURI(u)
+	if isURLRelative := location.Host == ""; isURLRelative {
+		location = &url.URL{Path: filepath.ToSlash(path)}
 	}
 
+	spec, err := p.readFromURI(location)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load spec: %w", err)
 	}
 
-	// we need to marshal the struct back to yaml while we support
-	// both openapi spec 2.0 and 3.0, so we can differentiate between the two
-	// and convert 2.0 to 3.0 if needed
-	bSpec, err := yaml.Marshal(&spec)
-	if err != nil {
-		return nil, fmt.Errorf("unable to marshal spec to yaml: %w", err)
-	}
-
-	return p.ParseFromReader(bytes.NewReader(bSpec))
+	return p.parse(spec, location)
 }
 
 // ParseFromReader allows for providing your own Reader implementation
-// to parse the API spec from
+// to parse the API spec from, references to other files are resolved relative to the working directory
 func (p Parser) ParseFromReader(contents io.Reader) (*openapi3.T, error) {
 	spec, err := ioutil.ReadAll(contents)
 	if err != nil {
 		return nil, fmt.Errorf("could not read contents of api spec: %w", err)
 	}
 
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("could not get working directory: %w", err)
+	}
+
+	// the trailing slash makes the working directory itself the base of relative references
+	return p.parse(spec, &url.URL{Path: filepath.ToSlash(wd) + "/"})
+}
+
+// parse parses either an OpenAPI 2.0 (swagger) spec, converting it to OpenAPI 3.0, or an OpenAPI 3.x spec found at location.
+// OpenAPI 3.1 specs are downgraded to OpenAPI 3.0, keeping the original version in the parsed spec.
+func (p Parser) parse(spec []byte, location *url.URL) (*openapi3.T, error) {
 	if isSwagger(spec) {
 		return parseSwagger(spec)
 	}
 
-	return parseOpenAPI3(spec)
+	if isOpenAPI31(spec) {
+		var err error
+
+		spec, err = downgradeOpenAPI31(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert OpenAPI 3.1 spec: %w", err)
+		}
+	}
+
+	res, err := p.loader.LoadFromDataWithPath(spec, location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load spec: %w", err)
+	}
+
+	return res, nil
+}
+
+func readFromURI(location *url.URL) ([]byte, error) {
+	if location.Host == "" {
+		return ioutil.ReadFile(location.Path)
+	}
+
+	resp, err := http.Get(location.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("error loading %s: request returned status code %d", location, resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
 }
 
 func parseSwagger(spec []byte) (*openapi3.T, error) {
@@ -103,7 +182,3 @@ func parseSwagger(spec []byte) (*openapi3.T, error) {
 
 	return openapi2conv.ToV3(&swaggerSpec)
 }
-
-func parseOpenAPI3(spec []byte) (*openapi3.T, error) {
-	return openapi3.NewLoader().LoadFromData(spec)
-}
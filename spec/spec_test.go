@@ -2,6 +2,8 @@ package spec
 
 import (
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -9,68 +11,98 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-const (
-	loadedFromURI  = "loaded from URI"
-	loadedFromFile = "loaded from file"
-)
-
+// mockLoader describes the spec with the location it was loaded from
 type mockLoader struct{}
 
-func (m mockLoader) LoadFromURI(_ *url.URL) (*openapi3.T, error) {
+func (m mockLoader) LoadFromDataWithPath(data []byte, location *url.URL) (*openapi3.T, error) {
 	return &openapi3.T{
 		OpenAPI: "3.0.3",
 		Info: &openapi3.Info{
-			Title:       "Sample API",
-			Description: loadedFromURI,
+			Title:       string(data),
+			Description: location.String(),
 			Version:     "1.0.0",
 		},
 	}, nil
 }
 
-func (m mockLoader) LoadFromFile(_ string) (*openapi3.T, error) {
-	return &openapi3.T{
-		OpenAPI: "3.0.3",
-		Info: &openapi3.Info{
-			Title:       "Sample API",
-			Description: loadedFromFile,
-			Version:     "1.0.0",
-		},
-	}, nil
+func mockReadFromURI(location *url.URL) ([]byte, error) {
+	if location.Host != "" {
+		return []byte("read from URI"), nil
+	}
+
+	return []byte("read from file"), nil
 }
 
 func TestParse(t *testing.T) {
 	testCases := []struct {
-		name   string
-		url    string
-		result string
+		name     string
+		url      string
+		data     string
+		location string
 	}{
 		{
-			name:   "load spec from url",
-			url:    "https://someurl.io/swagger.yaml",
-			result: loadedFromURI,
+			name:     "load spec from url",
+			url:      "https://someurl.io/swagger.yaml",
+			data:     "read from URI",
+			location: "https://someurl.io/swagger.yaml",
+		},
+		{
+			name:     "load spec from local file",
+			url:      "some-folder/swagger.yaml",
+			data:     "read from file",
+			location: "some-folder/swagger.yaml",
 		},
 		{
-			name:   "load spec from local file",
-			url:    "some-folder/swagger.yaml",
-			result: loadedFromFile,
+			name: "load spec from stdin",
+			url:  StdinPath,
+			data: "read from stdin",
 		},
 	}
 
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			r := require.New(t)
 
-			parser := Parser{loader: mockLoader{}}
-			u, err := url.Parse(testCase.url)
-			r.NoError(err, "please provide a valid url")
+			parser := Parser{
+				loader:      mockLoader{},
+				readFromURI: mockReadFromURI,
+				stdin:       strings.NewReader("read from stdin"),
+			}
 
-			actual, err := parser.Parse(u.String())
+			actual, err := parser.Parse(testCase.url)
 			r.NoError(err, "expected no error when running parse from mocked loader")
-			r.True(actual.Info.Description == testCase.result)
+			r.Equal(testCase.data, actual.Info.Title)
+
+			// specs read from stdin are resolved relative to the working directory
+			if testCase.url == StdinPath {
+				r.Equal(filepath.ToSlash(wd)+"/", actual.Info.Description)
+				return
+			}
+
+			r.Equal(testCase.location, actual.Info.Description)
 		})
 	}
 }
 
+func TestParseMultipleFiles(t *testing.T) {
+	r := require.New(t)
+
+	actual, err := NewParser(NewLoader()).Parse("testdata/multi-file/api.yaml")
+	r.NoError(err)
+
+	books := actual.Paths.Find("/books")
+	r.NotNil(books)
+
+	// paths and schemas referenced from sibling files, and a reference between those files
+	r.NotNil(books.Get)
+	book := books.Get.Responses.Get(200).Value.Content.Get("application/json").Schema.Value.Items.Value
+	r.Equal("string", book.Properties["title"].Value.Type)
+	r.Equal("string", book.Properties["author"].Value.Properties["name"].Value.Type)
+}
+
 func TestParseFromReader(t *testing.T) {
 	testCases := []struct {
 		name   string
@@ -133,7 +165,7 @@ paths:
 		t.Run(testCase.name, func(t *testing.T) {
 			r := require.New(t)
 
-			actual, err := Parser{loader: openapi3.NewLoader()}.ParseFromReader(strings.NewReader(testCase.spec))
+			actual, err := Parser{loader: NewLoader()}.ParseFromReader(strings.NewReader(testCase.spec))
 			r.NoError(err, "failed to parse spec from reader")
 			r.Equal(testCase.result.OpenAPI, actual.OpenAPI)
 			r.Equal(testCase.result.Info.Title, actual.Info.Title)
@@ -144,3 +176,54 @@ paths:
 
 	}
 }
+
+func TestParseOpenAPI31(t *testing.T) {
+	r := require.New(t)
+
+	actual, err := NewParser(NewLoader()).Parse("testdata/openapi31/api.yaml")
+	r.NoError(err)
+
+	r.Equal("3.1.0", actual.OpenAPI)
+
+	// path items referenced from components, with the fields next to the reference kept
+	books := actual.Paths.Find("/books")
+	r.NotNil(books)
+	r.NotNil(books.Get)
+
+	book := actual.Paths.Find("/books/{id}")
+	r.NotNil(book)
+	r.NotNil(book.Get)
+	r.Equal("a single book", book.Description)
+
+	// JSON Schema 2020-12 keywords, also in the referenced file
+	id := book.Get.Parameters.GetByInAndName("path", "id").Schema.Value
+	r.Equal("integer", id.Type)
+	r.Equal(0.0, *id.Min)
+	r.True(id.ExclusiveMin)
+
+	schema := books.Get.Responses.Get(200).Value.Content.Get("application/json").Schema.Value.Items.Value
+	r.Equal("string", schema.Properties["title"].Value.Type)
+
+	subtitle := schema.Properties["subtitle"].Value
+	r.Equal("string", subtitle.Type)
+	r.True(subtitle.Nullable)
+
+	isbn := schema.Properties["isbn"].Value
+	r.Empty(isbn.Type)
+	r.Len(isbn.AnyOf, 2)
+	r.Equal("string", isbn.AnyOf[0].Value.Type)
+	r.Equal("integer", isbn.AnyOf[1].Value.Type)
+
+	r.Equal([]interface{}{"book"}, schema.Properties["kind"].Value.Enum)
+
+	rating := schema.Properties["rating"].Value
+	r.Equal(1.0, *rating.Min)
+	r.False(rating.ExclusiveMin)
+	r.Equal(5.0, *rating.Max)
+	r.True(rating.ExclusiveMax)
+
+	// a property named like a keyword holding values
+	defaultProperty := schema.Properties["default"].Value
+	r.Equal("boolean", defaultProperty.Type)
+	r.True(defaultProperty.Nullable)
+}
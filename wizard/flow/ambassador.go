@@ -43,19 +43,19 @@ func (a ambassadorFlow) shouldSeparateMappings(basePath string) bool {
 func (a ambassadorFlow) getTimeoutOpts() options.TimeoutOptions {
 	var timeoutOptions options.TimeoutOptions
 
-	if requestTimeout := a.prompt.Input("Request timeout, leave empty to skip", strconv.Itoa(int(a.opts.Timeouts.RequestTimeout))); requestTimeout != "" {
-		if rTimeout, err := strconv.Atoi(requestTimeout); err != nil {
+	if requestTimeout := a.prompt.Input("Request timeout, leave empty to skip", a.opts.Timeouts.RequestTimeout.String()); requestTimeout != "" {
+		if rTimeout, err := options.ParseDuration(requestTimeout); err != nil {
 			log.Printf("WARN: %s is not a valid request timeout value. Skipping\n", requestTimeout)
 		} else {
-			timeoutOptions.RequestTimeout = uint32(rTimeout)
+			timeoutOptions.RequestTimeout = rTimeout
 		}
 	}
 
-	if idleTimeout := a.prompt.Input("Idle timeout, leave empty to skip", strconv.Itoa(int(a.opts.Timeouts.IdleTimeout))); idleTimeout != "" {
-		if iTimeout, err := strconv.Atoi(idleTimeout); err != nil {
+	if idleTimeout := a.prompt.Input("Idle timeout, leave empty to skip", a.opts.Timeouts.IdleTimeout.String()); idleTimeout != "" {
+		if iTimeout, err := options.ParseDuration(idleTimeout); err != nil {
 			log.Printf("WARN: %s is not a valid idle timeout value. Skipping\n", idleTimeout)
 		} else {
-			timeoutOptions.RequestTimeout = uint32(iTimeout)
+			timeoutOptions.IdleTimeout = iTimeout
 		}
 	}
 
@@ -135,10 +135,10 @@ func (a ambassadorFlow) getCmdFromOpts(opts *options.Options) string {
 	}
 
 	if opts.Timeouts.RequestTimeout > 0 {
-		cmd = cmd + fmt.Sprintf("--timeouts.request_timeout=%d", opts.Timeouts.RequestTimeout)
+		cmd = cmd + fmt.Sprintf("--timeouts.request_timeout=%s", opts.Timeouts.RequestTimeout)
 	}
 	if opts.Timeouts.IdleTimeout > 0 {
-		cmd = cmd + fmt.Sprintf("--timeouts.idle_timeout=%d", opts.Timeouts.IdleTimeout)
+		cmd = cmd + fmt.Sprintf("--timeouts.idle_timeout=%s", opts.Timeouts.IdleTimeout)
 	}
 
 	return cmd
@@ -154,6 +154,8 @@ func (a ambassadorFlow) Start() (Response, error) {
 
 	opts := &options.Options{
 		Namespace: a.targetNamespace,
+		// x-kusk metadata options of the spec aren't prompted for
+		Metadata: a.opts.Metadata,
 		Service: options.ServiceOptions{
 			Namespace: a.targetNamespace,
 			Name:      a.targetService,
@@ -74,6 +74,8 @@ func New(args *Args) (Interface, error) {
 		return nginxIngressFlow{baseFlow}, nil
 	case "traefik":
 		return traefikFlow{baseFlow}, nil
+	case "istio":
+		return istioFlow{baseFlow}, nil
 	default:
 		return nil, fmt.Errorf("unsupported service: %s\n", args.Service)
 	}
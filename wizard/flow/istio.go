@@ -0,0 +1,149 @@
+package flow
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/kubeshop/kusk-gen/generators/istio"
+	"github.com/kubeshop/kusk-gen/options"
+)
+
+type istioFlow struct {
+	baseFlow
+}
+
+func (i istioFlow) getBasePath() string {
+	var basePathSuggestions []string
+	for _, server := range i.apiSpec.Servers {
+		basePathSuggestions = append(basePathSuggestions, server.URL)
+	}
+
+	if len(basePathSuggestions) == 0 && i.opts.Path.Base != "" {
+		basePathSuggestions = append(basePathSuggestions, i.opts.Path.Base)
+	}
+
+	return i.prompt.SelectOneOf("Base path prefix", basePathSuggestions, true)
+}
+
+func (i istioFlow) getTrimPrefix(basePath string) string {
+	trimPrefixDefault := basePath
+	if i.opts.Path.TrimPrefix != "" {
+		trimPrefixDefault = i.opts.Path.TrimPrefix
+	}
+
+	return i.prompt.Input("Prefix to trim from the URL (rewrite)", trimPrefixDefault)
+}
+
+func (i istioFlow) getHost() string {
+	return i.prompt.Input("Host (leave empty to route mesh traffic only)", i.opts.Host)
+}
+
+func (i istioFlow) getGateways() []string {
+	if len(i.opts.Istio.Gateways) > 0 &&
+		i.prompt.Confirm(fmt.Sprintf("attach to the following Istio Gateways? %s", i.opts.Istio.Gateways)) {
+		return i.opts.Istio.Gateways
+	}
+
+	return i.prompt.InputMany("add Istio Gateway (namespace/name)")
+}
+
+func (i istioFlow) getTimeoutOpts() options.TimeoutOptions {
+	var timeoutOptions options.TimeoutOptions
+
+	if requestTimeout := i.prompt.Input("Request timeout, leave empty to skip", i.opts.Timeouts.RequestTimeout.String()); requestTimeout != "" {
+		if rTimeout, err := options.ParseDuration(requestTimeout); err != nil {
+			log.Printf("WARN: %s is not a valid request timeout value. Skipping\n", requestTimeout)
+		} else {
+			timeoutOptions.RequestTimeout = rTimeout
+		}
+	}
+
+	if idleTimeout := i.prompt.Input("Idle timeout, leave empty to skip", i.opts.Timeouts.IdleTimeout.String()); idleTimeout != "" {
+		if iTimeout, err := options.ParseDuration(idleTimeout); err != nil {
+			log.Printf("WARN: %s is not a valid idle timeout value. Skipping\n", idleTimeout)
+		} else {
+			timeoutOptions.IdleTimeout = iTimeout
+		}
+	}
+
+	return timeoutOptions
+}
+
+func (i istioFlow) getCmdFromOpts(opts *options.Options) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("kusk istio -i %s ", i.apiSpecPath))
+	sb.WriteString(fmt.Sprintf("--namespace=%s ", i.targetNamespace))
+	sb.WriteString(fmt.Sprintf("--service.namespace=%s ", i.targetNamespace))
+	sb.WriteString(fmt.Sprintf("--service.name=%s ", i.targetService))
+	sb.WriteString(fmt.Sprintf("--path.base=%s ", opts.Path.Base))
+
+	if opts.Path.TrimPrefix != "" {
+		sb.WriteString(fmt.Sprintf("--path.trim_prefix=%s ", opts.Path.TrimPrefix))
+	}
+
+	if opts.Host != "" {
+		sb.WriteString(fmt.Sprintf("--host=%s ", opts.Host))
+	}
+
+	if len(opts.Istio.Gateways) > 0 {
+		sb.WriteString(fmt.Sprintf("--istio.gateways=%s ", strings.Join(opts.Istio.Gateways, ",")))
+	}
+
+	if opts.Timeouts.RequestTimeout > 0 {
+		sb.WriteString(fmt.Sprintf("--timeouts.request_timeout=%s ", opts.Timeouts.RequestTimeout))
+	}
+
+	if opts.Timeouts.IdleTimeout > 0 {
+		sb.WriteString(fmt.Sprintf("--timeouts.idle_timeout=%s ", opts.Timeouts.IdleTimeout))
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+func (i istioFlow) Start() (Response, error) {
+	basePath := i.getBasePath()
+	trimPrefix := i.getTrimPrefix(basePath)
+	host := i.getHost()
+
+	var gateways []string
+	if host != "" {
+		gateways = i.getGateways()
+	}
+
+	timeoutOptions := i.getTimeoutOpts()
+
+	opts := &options.Options{
+		Namespace: i.targetNamespace,
+		// x-kusk metadata options of the spec aren't prompted for
+		Metadata: i.opts.Metadata,
+		Service: options.ServiceOptions{
+			Namespace: i.targetNamespace,
+			Name:      i.targetService,
+		},
+		Path: options.PathOptions{
+			Base:       basePath,
+			TrimPrefix: trimPrefix,
+		},
+		Host: host,
+		Istio: options.IstioOptions{
+			Gateways: gateways,
+		},
+		Timeouts: timeoutOptions,
+		CORS:     i.opts.CORS,
+	}
+
+	cmd := i.getCmdFromOpts(opts)
+
+	var istioGenerator istio.Generator
+
+	manifests, err := istioGenerator.Generate(opts, i.apiSpec)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to generate Istio resources: %s\n", err)
+	}
+
+	return Response{
+		EquivalentCmd: cmd,
+		Manifests:     manifests,
+	}, nil
+}
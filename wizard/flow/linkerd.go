@@ -3,7 +3,6 @@ package flow
 import (
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/kubeshop/kusk-gen/generators/linkerd"
 	"github.com/kubeshop/kusk-gen/options"
@@ -39,11 +38,11 @@ func (l linkerdFlow) getTimeoutOpts() options.TimeoutOptions {
 	var timeoutOptions options.TimeoutOptions
 
 	// Support only request timeout as linkerd generator doesn't support idle timeout
-	if requestTimeout := l.prompt.Input("Request timeout, leave empty to skip", strconv.Itoa(int(l.opts.Timeouts.RequestTimeout))); requestTimeout != "" {
-		if rTimeout, err := strconv.Atoi(requestTimeout); err != nil {
+	if requestTimeout := l.prompt.Input("Request timeout, leave empty to skip", l.opts.Timeouts.RequestTimeout.String()); requestTimeout != "" {
+		if rTimeout, err := options.ParseDuration(requestTimeout); err != nil {
 			log.Printf("WARN: %s is not a valid request timeout value. Skipping\n", requestTimeout)
 		} else {
-			timeoutOptions.RequestTimeout = uint32(rTimeout)
+			timeoutOptions.RequestTimeout = rTimeout
 		}
 	}
 
@@ -59,7 +58,7 @@ func (l linkerdFlow) getCmdFromOpts(opts *options.Options) string {
 	cmd = cmd + fmt.Sprintf("--cluster.cluster_domain=%s ", opts.Cluster.ClusterDomain)
 
 	if opts.Timeouts.RequestTimeout > 0 {
-		cmd = cmd + fmt.Sprintf("--timeouts.request_timeout=%d", opts.Timeouts.RequestTimeout)
+		cmd = cmd + fmt.Sprintf("--timeouts.request_timeout=%s", opts.Timeouts.RequestTimeout)
 	}
 
 	return cmd
@@ -73,6 +72,8 @@ func (l linkerdFlow) Start() (Response, error) {
 
 	opts := &options.Options{
 		Namespace: l.targetNamespace,
+		// x-kusk metadata options of the spec aren't prompted for
+		Metadata: l.opts.Metadata,
 		Path: options.PathOptions{
 			Base: basePath,
 		},
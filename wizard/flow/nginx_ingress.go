@@ -44,11 +44,11 @@ func (n nginxIngressFlow) getTimeoutOpts() options.TimeoutOptions {
 	var timeoutOptions options.TimeoutOptions
 
 	// Support only request timeout as nginx-ingress generator doesn't support idle timeout
-	if requestTimeout := n.prompt.Input("Request timeout, leave empty to skip", strconv.Itoa(int(n.opts.Timeouts.RequestTimeout))); requestTimeout != "" {
-		if rTimeout, err := strconv.Atoi(requestTimeout); err != nil {
+	if requestTimeout := n.prompt.Input("Request timeout, leave empty to skip", n.opts.Timeouts.RequestTimeout.String()); requestTimeout != "" {
+		if rTimeout, err := options.ParseDuration(requestTimeout); err != nil {
 			log.Printf("WARN: %s is not a valid request timeout value. Skipping\n", requestTimeout)
 		} else {
-			timeoutOptions.RequestTimeout = uint32(rTimeout)
+			timeoutOptions.RequestTimeout = rTimeout
 		}
 	}
 
@@ -142,6 +142,8 @@ func (n nginxIngressFlow) Start() (Response, error) {
 
 	opts := &options.Options{
 		Namespace: n.targetNamespace,
+		// x-kusk metadata options of the spec aren't prompted for
+		Metadata: n.opts.Metadata,
 		Service: options.ServiceOptions{
 			Namespace: n.targetNamespace,
 			Name:      n.targetService,
@@ -39,18 +39,18 @@ func (t traefikFlow) getTrimPrefix(basePath string) string {
 func (t traefikFlow) getTimeoutOpts() options.TimeoutOptions {
 	var timeoutOptions options.TimeoutOptions
 
-	if requestTimeout := t.prompt.Input("Request timeout, leave empty to skip", strconv.Itoa(int(t.opts.Timeouts.RequestTimeout))); requestTimeout != "" {
-		if rTimeout, err := strconv.Atoi(requestTimeout); err != nil {
+	if requestTimeout := t.prompt.Input("Request timeout, leave empty to skip", t.opts.Timeouts.RequestTimeout.String()); requestTimeout != "" {
+		if rTimeout, err := options.ParseDuration(requestTimeout); err != nil {
 			log.Printf("WARN: %s is not a valid request timeout value. Skipping\n", requestTimeout)
 		} else {
-			timeoutOptions.RequestTimeout = uint32(rTimeout)
+			timeoutOptions.RequestTimeout = rTimeout
 		}
 	}
-	if idleTimeout := t.prompt.Input("Idle timeout, leave empty to skip", strconv.Itoa(int(t.opts.Timeouts.IdleTimeout))); idleTimeout != "" {
-		if iTimeout, err := strconv.Atoi(idleTimeout); err != nil {
+	if idleTimeout := t.prompt.Input("Idle timeout, leave empty to skip", t.opts.Timeouts.IdleTimeout.String()); idleTimeout != "" {
+		if iTimeout, err := options.ParseDuration(idleTimeout); err != nil {
 			log.Printf("WARN: %s is not a valid idle timeout value. Skipping\n", idleTimeout)
 		} else {
-			timeoutOptions.RequestTimeout = uint32(iTimeout)
+			timeoutOptions.IdleTimeout = iTimeout
 		}
 	}
 
@@ -132,10 +132,10 @@ func (t traefikFlow) getCmdFromOpts(opts *options.Options) string {
 	}
 
 	if opts.Timeouts.RequestTimeout > 0 {
-		sb.WriteString(fmt.Sprintf("--timeouts.request_timeout=%d", opts.Timeouts.RequestTimeout))
+		sb.WriteString(fmt.Sprintf("--timeouts.request_timeout=%s", opts.Timeouts.RequestTimeout))
 	}
 	if opts.Timeouts.IdleTimeout > 0 {
-		sb.WriteString(fmt.Sprintf("--timeouts.idle_timeout=%d", opts.Timeouts.IdleTimeout))
+		sb.WriteString(fmt.Sprintf("--timeouts.idle_timeout=%s", opts.Timeouts.IdleTimeout))
 	}
 	return sb.String()
 }
@@ -149,6 +149,8 @@ func (t traefikFlow) Start() (Response, error) {
 
 	opts := &options.Options{
 		Namespace: t.targetNamespace,
+		// x-kusk metadata options of the spec aren't prompted for
+		Metadata: t.opts.Metadata,
 		Service: options.ServiceOptions{
 			Namespace: t.targetNamespace,
 			Name:      t.targetService,
@@ -92,6 +92,11 @@ func flowWithCluster(args *flow.Args, kubeConfigPath string) (string, error) {
 		return "", fmt.Errorf("failed to check if nginx ingress is installed: %w", err)
 	}
 
+	istioFound, err := client.DetectIstio()
+	if err != nil {
+		return "", fmt.Errorf("failed to check if Istio is installed: %w", err)
+	}
+
 	traefikFound, err := client.DetectTraefikV2()
 	if err != nil {
 		return "", fmt.Errorf("failed to check if traefik is installed: %w", err)
@@ -112,6 +117,11 @@ func flowWithCluster(args *flow.Args, kubeConfigPath string) (string, error) {
 		fmt.Fprintln(os.Stderr, "✔ Ingress Nginx installation found")
 	}
 
+	if istioFound {
+		servicesToSuggest = append(servicesToSuggest, "istio")
+		fmt.Fprintln(os.Stderr, "✔ Istio installation found")
+	}
+
 	if traefikFound {
 		servicesToSuggest = append(servicesToSuggest, "traefik")
 		fmt.Fprintln(os.Stderr, "✔ Traefik installation found")
@@ -156,6 +166,7 @@ func flowWithoutCluster(args *flow.Args) (string, error) {
 			"linkerd",
 			"ingress-nginx",
 			"traefik",
+			"istio",
 		},
 		false,
 	)